@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ── Corner actions ──────────────────────────────────────────────────────────
+// What click mode does once the cursor is on the button: a left, right or
+// middle click, a double-click, some scroll-wheel ticks or nothing. Each
+// press can be held down for a while. One action applies to every corner,
+// or four give one per corner.
+
+type mouseButton int
+
+const (
+	buttonLeft mouseButton = iota
+	buttonRight
+	buttonMiddle
+)
+
+type actionKind string
+
+const (
+	actLeft   actionKind = "left"
+	actRight  actionKind = "right"
+	actMiddle actionKind = "middle"
+	actDouble actionKind = "double" // two left clicks, the second with click count 2
+	actScroll actionKind = "scroll"
+	actNone   actionKind = "none" // glide there and do nothing
+)
+
+var actionKinds = []actionKind{actLeft, actRight, actMiddle, actDouble, actScroll, actNone}
+
+const (
+	doubleClickGap = 90 * time.Millisecond // between the presses of a double-click
+	scrollTickGap  = 40 * time.Millisecond // between wheel ticks
+	maxHoldMs      = 5000
+	maxScrollTicks = 20
+)
+
+type action struct {
+	Kind   actionKind `json:"kind"`    // left, right, middle, double, scroll or none
+	HoldMs int        `json:"hold_ms"` // how long each press stays down
+	Ticks  int        `json:"ticks"`   // scroll: wheel ticks, positive scrolls down
+}
+
+func (a action) validate() error {
+	switch a.Kind {
+	case actLeft, actRight, actMiddle, actDouble, actNone:
+	case actScroll:
+		if a.Ticks == 0 || a.Ticks < -maxScrollTicks || a.Ticks > maxScrollTicks {
+			return fmt.Errorf("actions: scroll needs ticks -%d..%d (not 0), got %d", maxScrollTicks, maxScrollTicks, a.Ticks)
+		}
+	default:
+		return fmt.Errorf("actions: kind must be one of %v, got %q", actionKinds, a.Kind)
+	}
+	if a.HoldMs < 0 || a.HoldMs > maxHoldMs {
+		return fmt.Errorf("actions: hold_ms must be 0..%d, got %d", maxHoldMs, a.HoldMs)
+	}
+	return nil
+}
+
+// validateActions accepts one action for every corner or one per corner.
+func validateActions(as []action) error {
+	if len(as) != 1 && len(as) != 4 {
+		return fmt.Errorf("actions: need 1 action or 4 (one per corner), got %d", len(as))
+	}
+	for _, a := range as {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// actionAt returns the action for corner i, in corners() order.
+func (c *config) actionAt(i int) action {
+	if len(c.Actions) == 1 {
+		return c.Actions[0]
+	}
+	return c.Actions[i%len(c.Actions)]
+}
+
+// act performs a with the cursor on the button. Every press is released
+// again, even if ctx ends while it is held.
+func (e *engine) act(ctx context.Context, a action) error {
+	hold := ms(a.HoldMs)
+	switch a.Kind {
+	case actNone:
+		return nil
+	case actRight:
+		return e.press(ctx, buttonRight, 1, hold)
+	case actMiddle:
+		return e.press(ctx, buttonMiddle, 1, hold)
+	case actDouble:
+		if err := e.press(ctx, buttonLeft, 1, hold); err != nil {
+			return err
+		}
+		if !sleepWithCancel(ctx, doubleClickGap) {
+			return ctx.Err()
+		}
+		return e.press(ctx, buttonLeft, 2, hold)
+	case actScroll:
+		step, n := 1, a.Ticks
+		if n < 0 {
+			step, n = -1, -n
+		}
+		for i := 0; i < n; i++ {
+			if i > 0 && !sleepWithCancel(ctx, scrollTickGap) {
+				return ctx.Err()
+			}
+			e.p.Scroll(step)
+		}
+		return nil
+	}
+	return e.press(ctx, buttonLeft, 1, hold)
+}
+
+// press holds b down for hold. count is the click count the OS should see,
+// 2 for the second press of a double-click. lastClick is refreshed on the
+// way up too, so a long hold still counts as ours when the button fires.
+func (e *engine) press(ctx context.Context, b mouseButton, count int, hold time.Duration) error {
+	e.lastClick.Store(time.Now().UnixNano())
+	e.p.MouseDown(b, count)
+	held := hold == 0 || sleepWithCancel(ctx, hold)
+	e.lastClick.Store(time.Now().UnixNano())
+	e.p.MouseUp(b, count)
+	if !held {
+		return ctx.Err()
+	}
+	return nil
+}
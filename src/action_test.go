@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// pressLog renders the presses, releases and wheel ticks r recorded, e.g.
+// "down 0/1 up 0/1" for a left click.
+func pressLog(r *recordPlatform) string {
+	var s string
+	for _, ev := range r.Events() {
+		switch ev.Kind {
+		case evMouseDown, evMouseUp:
+			s += fmt.Sprintf("%s %d/%d ", ev.Kind, ev.Button, ev.N)
+		case evScroll:
+			s += fmt.Sprintf("%s %d ", ev.Kind, ev.N)
+		}
+	}
+	return s
+}
+
+func TestAct(t *testing.T) {
+	for _, tc := range []struct {
+		a    action
+		want string
+		min  time.Duration // the action takes at least this long
+	}{
+		{action{Kind: actLeft}, "down 0/1 up 0/1 ", 0},
+		{action{Kind: actRight}, "down 1/1 up 1/1 ", 0},
+		{action{Kind: actMiddle}, "down 2/1 up 2/1 ", 0},
+		{action{Kind: actDouble}, "down 0/1 up 0/1 down 0/2 up 0/2 ", doubleClickGap},
+		{action{Kind: actLeft, HoldMs: 50}, "down 0/1 up 0/1 ", 50 * time.Millisecond},
+		{action{Kind: actDouble, HoldMs: 20}, "down 0/1 up 0/1 down 0/2 up 0/2 ", doubleClickGap + 40*time.Millisecond},
+		{action{Kind: actScroll, Ticks: 3}, "scroll 1 scroll 1 scroll 1 ", 2 * scrollTickGap},
+		{action{Kind: actScroll, Ticks: -2}, "scroll -1 scroll -1 ", scrollTickGap},
+		{action{Kind: actNone}, "", 0},
+	} {
+		r := newRecordPlatform(fastConfig().Window, 0, 0)
+		e := newEngine(r, fastConfig())
+		start := time.Now()
+		if err := e.act(context.Background(), tc.a); err != nil {
+			t.Errorf("%+v: %v", tc.a, err)
+		}
+		if took := time.Since(start); took < tc.min {
+			t.Errorf("%+v: took %v, want at least %v", tc.a, took, tc.min)
+		}
+		if got := pressLog(r); got != tc.want {
+			t.Errorf("%+v: %q, want %q", tc.a, got, tc.want)
+		}
+	}
+}
+
+// TestActCancelled ends ctx part way through each action: whatever is held
+// is released, nothing more is pressed and act returns ctx.Err().
+func TestActCancelled(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		a       action
+		presses int // cancel once this many presses or ticks were sent
+		want    string
+	}{
+		{"holding left", action{Kind: actLeft, HoldMs: maxHoldMs}, 1, "down 0/1 up 0/1 "},
+		{"holding right", action{Kind: actRight, HoldMs: maxHoldMs}, 1, "down 1/1 up 1/1 "},
+		{"holding the first of a double", action{Kind: actDouble, HoldMs: maxHoldMs}, 1, "down 0/1 up 0/1 "},
+		{"holding the second of a double", action{Kind: actDouble, HoldMs: 300}, 3, "down 0/1 up 0/1 down 0/2 up 0/2 "},
+		{"between the clicks of a double", action{Kind: actDouble}, 2, "down 0/1 up 0/1 "},
+		{"between wheel ticks", action{Kind: actScroll, Ticks: maxScrollTicks}, 1, "scroll 1 "},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newRecordPlatform(fastConfig().Window, 0, 0)
+			e := newEngine(r, fastConfig())
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errc := make(chan error, 1)
+			go func() { errc <- e.act(ctx, tc.a) }()
+
+			waitUntil(t, "the presses", func() bool {
+				return len(r.EventsOf(evMouseDown))+len(r.EventsOf(evMouseUp))+len(r.EventsOf(evScroll)) >= tc.presses
+			})
+			cancel()
+			select {
+			case err := <-errc:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("act returned %v, want %v", err, context.Canceled)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("act did not return when ctx ended")
+			}
+			if got := pressLog(r); got != tc.want {
+				t.Errorf("%q, want %q", got, tc.want)
+			}
+		})
+	}
+}
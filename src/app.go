@@ -1,175 +1,481 @@
 package main
 
 import (
-	"math"
+	"context"
+	"errors"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 )
 
 // ── Platform contract ───────────────────────────────────────────────────────
-// Each platform_*.go must implement these functions:
-//
-//   platformRun()                              – create GUI + run event loop (blocks)
-//   platformSetCursorPos(x, y int)             – move cursor (screen coords)
-//   platformGetCursorPos() (int, int)           – get cursor position
-//   platformClick()                             – simulate left click
-//   platformPreventSleep()                      – prevent system sleep
-//   platformAllowSleep()                        – allow system sleep
-//   platformMoveButton(x, y int)                – move button (client coords)
-//   platformClientToScreen(x, y int) (int, int) – convert client → screen coords
-//   platformSetButtonActive(isActive bool)       – change button appearance
-//   platformReinforceTopmost()                  – reinforce always-on-top
-//   platformQuit()                              – quit application
+// Each platform_*.go provides newPlatform() returning a Platform. The alive
+// engine only talks to the backend through these interfaces, so backends can
+// be swapped at runtime.
+
+// Window is the on-screen part of a backend: the client area and the button.
+type Window interface {
+	MoveButton(x, y int)                // move button (client coords)
+	ClientToScreen(x, y int) (int, int) // convert client → screen coords
+	SetButtonActive(isActive bool)      // change button appearance
+	SetModeLabel(text string)           // change mode selector text
+	SetStatus(text string)              // status line above the idle button ("" hides it)
+	SetTimerLabel(text string)          // change timer selector text
+	ReinforceTopmost()                  // reinforce always-on-top
+}
+
+// SleepInhibitor keeps the system and display awake.
+type SleepInhibitor interface {
+	PreventSleep() // prevent system sleep
+	AllowSleep()   // allow system sleep
+}
+
+// Platform is the full backend used by the alive engine.
+type Platform interface {
+	Window
+	SleepInhibitor
+
+	Run()                               // create GUI + run event loop (blocks)
+	SetCursorPos(x, y int)              // move cursor (screen coords)
+	GetCursorPos() (int, int)           // get cursor position
+	ScreenSize() (int, int)             // main screen size (screen coords)
+	MouseDown(b mouseButton, count int) // press b; count is the click count (2 = second press of a double-click)
+	MouseUp(b mouseButton, count int)   // release b
+	Scroll(ticks int)                   // wheel ticks; positive scrolls down
+	TapKey(k key)                       // simulate a press and release of k
+	CursorOverButton() bool             // is the topmost window under the cursor our button?
+	IdleTime() time.Duration            // time since the last input event, ours included
+	Quit()                              // quit application
+}
 
 // ── Callbacks (set here, called by platform) ────────────────────────────────
 
 var onButtonClicked func()
 var onHotkeyQuit func()
+var onModeClicked func()
+var onTimerClicked func()
+var onWindowReady func() // window exists; safe to start the engine
 
 // ── Shared state ────────────────────────────────────────────────────────────
 
+// active mirrors whether the engine is running, for platform painters.
 var active atomic.Bool
 
-// ── Layout constants ────────────────────────────────────────────────────────
+// ── Modes ───────────────────────────────────────────────────────────────────
+
+type mode string
 
 const (
-	clientW = 300
-	clientH = 300
-	btnW    = 80
-	btnH    = 30
-	pad     = 10
+	modeClick mode = "click" // glide to the button and click it
+	modeMove  mode = "move"  // glide to the button, never click
+	modeKey   mode = "key"   // tap the keep-alive key; never touch the mouse
+	modeSleep mode = "sleep" // hold the sleep assertion only; never touch the mouse
 )
 
-// Button corner positions inside the client area
-var btnCorners = [4][2]int{
-	{pad, pad},                               // top-left
-	{clientW - btnW - pad, pad},              // top-right
-	{clientW - btnW - pad, clientH - btnH - pad}, // bottom-right
-	{pad, clientH - btnH - pad},              // bottom-left
+// modes is the order the mode selector cycles through.
+var modes = []mode{modeClick, modeMove, modeKey, modeSleep}
+
+func (m mode) valid() bool {
+	for _, v := range modes {
+		if m == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (m mode) next() mode {
+	for i, v := range modes {
+		if m == v {
+			return modes[(i+1)%len(modes)]
+		}
+	}
+	return modes[0]
+}
+
+func (m mode) label() string {
+	switch m {
+	case modeMove:
+		return "Move only"
+	case modeKey:
+		return "Keys only"
+	case modeSleep:
+		return "Sleep only"
+	}
+	return "Move + click"
+}
+
+// ── Keys ────────────────────────────────────────────────────────────────────
+// Some remote desktop and VDI sessions ignore synthetic mouse input but pass
+// keys through. Both keep-alive keys are harmless: F15 is on almost no
+// keyboard and nothing binds it, and Shift on its own types nothing.
+
+type key string
+
+const (
+	keyF15   key = "f15"
+	keyShift key = "shift"
+)
+
+var keys = []key{keyF15, keyShift}
+
+func (k key) valid() bool {
+	for _, v := range keys {
+		if k == v {
+			return true
+		}
+	}
+	return false
 }
 
 // ── Init ────────────────────────────────────────────────────────────────────
 
-func initApp() {
-	onButtonClicked = handleButtonClick
-	onHotkeyQuit = handleQuit
+func initApp(e *engine) {
+	onButtonClicked = func() { handleButtonClick(e) }
+	onHotkeyQuit = func() { handleQuit(e) }
+	onModeClicked = func() { e.SetMode(e.Mode().next()) }
 }
 
-func handleButtonClick() {
-	if !active.Load() {
-		active.Store(true)
-		platformSetButtonActive(true)
-		go aliveLoop()
+func handleButtonClick(e *engine) {
+	if e.Running() {
+		if e.clickedRecently() {
+			return
+		}
+		e.Stop()
+	} else {
+		e.Start()
 	}
 }
 
-func handleQuit() {
-	active.Store(false)
-	platformQuit()
+// handleQuit stops the engine and quits once the loop has returned, so it
+// never touches a window that is already gone.
+func handleQuit(e *engine) {
+	e.Stop()
+	go func() {
+		e.Wait()
+		e.p.Quit()
+	}()
 }
 
-// ── Random delay ────────────────────────────────────────────────────────────
+// ── Engine ──────────────────────────────────────────────────────────────────
 
-// sleepWithCancel sleeps for the given duration, checking active every 100ms.
-func sleepWithCancel(d time.Duration) {
-	end := time.Now().Add(d)
-	for time.Now().Before(end) && active.Load() {
-		remaining := time.Until(end)
-		if remaining > 100*time.Millisecond {
-			time.Sleep(100 * time.Millisecond)
-		} else {
-			time.Sleep(remaining)
-		}
+// Button clicks this soon after aliveLoop clicked are our own and must not
+// toggle the engine off.
+const syntheticClickGrace = 500 * time.Millisecond
+
+// engine owns the alive loop. At most one loop runs at a time, under a
+// context that Stop cancels.
+type engine struct {
+	p     *inputTracker // the backend, noting our own input for idle detection
+	cfg   *config
+	delay delayDist // pause between cycles
+	glide glide     // path and speed of each cursor move
+	seed  int64
+	rng   *rand.Rand // all of the loop's randomness; only the loop goroutine uses it
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // nil while stopped
+	done   chan struct{}      // closed when the most recent loop returns
+
+	lastClick     atomic.Int64 // UnixNano of the last synthetic click
+	skippedClicks atomic.Int64 // clicks not sent because the target was not ours
+
+	modeMu sync.Mutex
+	mode   mode // read by the loop at the start of every cycle
+
+	onFailsafe func() // after the fail-safe stops the engine; set before Start
+}
+
+func newEngine(p Platform, cfg *config) *engine {
+	// Both validated with the config
+	delay, _ := newDelayDist(cfg.Timing)
+	glide, _ := newGlide(cfg.Curve, cfg.Window.ButtonWidth)
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
 	}
+	e := &engine{
+		p:     &inputTracker{Platform: p},
+		cfg:   cfg,
+		delay: delay,
+		glide: glide,
+		seed:  seed,
+		rng:   rand.New(rand.NewSource(seed)),
+		mode:  cfg.Mode,
+	}
+	p.SetModeLabel(e.mode.label())
+	return e
+}
+
+// Seed returns the seed of the engine's random source; setting it as the
+// config seed replays the same delays and curves.
+func (e *engine) Seed() int64 {
+	return e.seed
 }
 
-// randomDelay waits 1-5 seconds with cancellation support.
-func randomDelay() {
-	delay := time.Duration(1000+rand.Intn(4001)) * time.Millisecond
-	sleepWithCancel(delay)
+func (e *engine) Mode() mode {
+	e.modeMu.Lock()
+	defer e.modeMu.Unlock()
+	return e.mode
 }
 
-// ── Bézier curve movement ───────────────────────────────────────────────────
+// SetMode switches mode; a running loop picks it up on its next cycle.
+func (e *engine) SetMode(m mode) {
+	e.modeMu.Lock()
+	e.mode = m
+	e.modeMu.Unlock()
+	e.p.SetModeLabel(m.label())
+}
+
+// Start runs a new aliveLoop. If the previous loop is still shutting down,
+// the new one waits for it so its AllowSleep cannot undo our PreventSleep.
+func (e *engine) Start() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	prev := e.done
+	done := make(chan struct{})
+	e.cancel, e.done = cancel, done
 
-func moveCursorAlongCurve(toX, toY int) {
-	fromX, fromY := platformGetCursorPos()
+	active.Store(true)
+	e.p.SetButtonActive(true)
 
-	dx := float64(toX - fromX)
-	dy := float64(toY - fromY)
-	dist := math.Sqrt(dx*dx + dy*dy)
+	go func() {
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
+		if ctx.Err() == nil {
+			e.aliveLoop(ctx)
+		}
+	}()
+}
 
-	if dist < 2 {
-		platformSetCursorPos(toX, toY)
+// Stop cancels the running loop and restores the idle button. It does not
+// wait for the loop to return; use Wait for that.
+func (e *engine) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel == nil {
 		return
 	}
 
-	// Perpendicular unit vector
-	perpX := -dy / dist
-	perpY := dx / dist
+	e.cancel()
+	e.cancel = nil
 
-	// Random offset amplitude: [-dist/3, +dist/3]
-	amplitude := (rand.Float64()*2 - 1) * dist / 3
+	active.Store(false)
+	e.p.SetButtonActive(false)
+}
+
+// Running reports whether the engine has been started and not stopped.
+func (e *engine) Running() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cancel != nil
+}
 
-	// Control point = midpoint + perpendicular offset
-	midX := float64(fromX) + dx/2
-	midY := float64(fromY) + dy/2
-	cpX := midX + perpX*amplitude
-	cpY := midY + perpY*amplitude
+// Wait blocks until the most recently started loop has returned.
+func (e *engine) Wait() {
+	e.mu.Lock()
+	done := e.done
+	e.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// SkippedClicks returns how many clicks the pre-click check has refused.
+func (e *engine) SkippedClicks() int64 {
+	return e.skippedClicks.Load()
+}
 
-	const steps = 25
-	const stepDelay = 4 * time.Millisecond
+func (e *engine) clickedRecently() bool {
+	return time.Since(time.Unix(0, e.lastClick.Load())) < syntheticClickGrace
+}
+
+// ── Random delay ────────────────────────────────────────────────────────────
+
+// sleepWithCancel sleeps for the given duration or until ctx is done. It
+// reports whether the full duration elapsed.
+func sleepWithCancel(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
 
+// ── Cursor movement ───────────────────────────────────────────────────
+
+// errCursorTaken means the user moved the cursor away from where we put it.
+var errCursorTaken = errors.New("cursor moved by user")
+
+// errFailsafe means the cursor hit the fail-safe corner.
+var errFailsafe = errors.New("fail-safe corner")
+
+// moveCursorAlongCurve glides the cursor to (toX, toY) as g plans it.
+// Before each step it runs failsafe (if non-nil) and checks that the cursor
+// is still within TolerancePx of where we left it; if not, the user has
+// grabbed the mouse and it returns errCursorTaken. It returns ctx.Err() as
+// soon as ctx is done. The path's randomness is drawn from r.
+func moveCursorAlongCurve(ctx context.Context, p Platform, g glide, r randSource, toX, toY int, failsafe func() bool) error {
+	fromX, fromY := p.GetCursorPos()
+	from, to := pointOf(fromX, fromY), pointOf(toX, toY)
+
+	if from.dist(to) < 2 {
+		p.SetCursorPos(toX, toY)
+		return nil
+	}
+
+	sw, sh := p.ScreenSize()
+	curve, steps, stepDelay := g.plan(from, to, pointOf(sw, sh), r)
+
+	lastX, lastY := fromX, fromY
 	for i := 1; i <= steps; i++ {
-		t := float64(i) / float64(steps)
-		inv := 1 - t
+		if failsafe != nil && failsafe() {
+			return errFailsafe
+		}
+		if cx, cy := p.GetCursorPos(); cursorDiverged(cx, cy, lastX, lastY, g.cc.TolerancePx) {
+			return errCursorTaken
+		}
 
-		// Quadratic Bézier: B(t) = (1-t)²·P0 + 2·(1-t)·t·P1 + t²·P2
-		x := inv*inv*float64(fromX) + 2*inv*t*cpX + t*t*float64(toX)
-		y := inv*inv*float64(fromY) + 2*inv*t*cpY + t*t*float64(toY)
+		lastX, lastY = curve(float64(i) / float64(steps)).round()
+		p.SetCursorPos(lastX, lastY)
+		if !sleepWithCancel(ctx, stepDelay) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
 
-		platformSetCursorPos(int(math.Round(x)), int(math.Round(y)))
-		time.Sleep(stepDelay)
+// cursorDiverged reports whether (x, y) is more than tol px from (wantX, wantY)
+// on either axis.
+func cursorDiverged(x, y, wantX, wantY, tol int) bool {
+	return abs(x-wantX) > tol || abs(y-wantY) > tol
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
 	}
+	return v
 }
 
 // ── Alive loop ──────────────────────────────────────────────────────────────
 
-func aliveLoop() {
-	platformPreventSleep()
-	defer platformAllowSleep()
-
-	idx := 0
-	for active.Load() {
-		c := btnCorners[idx%4]
+func (e *engine) aliveLoop(ctx context.Context) {
+	p, cfg := e.p, e.cfg
+	p.PreventSleep()
+	defer p.AllowSleep()
 
-		// Move button to next corner
-		platformMoveButton(c[0], c[1])
+	// The watcher ends with ctx, which is done whenever the loop returns
+	var watcher sync.WaitGroup
+	watcher.Add(1)
+	go func() {
+		defer watcher.Done()
+		e.watchFailsafe(ctx)
+	}()
+	defer watcher.Wait()
 
-		// Reinforce topmost
-		platformReinforceTopmost()
+	idx := 0
+	for ctx.Err() == nil {
+		m := e.Mode()
+		if m == modeSleep {
+			// Keep the assertion; check back for a mode change now and then
+			if !randomDelay(ctx, e.delay, e.rng) {
+				break
+			}
+			continue
+		}
 
-		sleepWithCancel(400 * time.Millisecond)
-		if !active.Load() {
+		// Leave the mouse alone while the user is using it
+		if !e.waitForIdle(ctx) {
 			break
 		}
+		cycleCtx, stopYield := e.yieldOnInput(ctx)
+		var err error
+		if m == modeKey {
+			err = e.keyCycle(cycleCtx)
+		} else {
+			err = e.cycle(cycleCtx, m, idx%4)
+		}
+		stopYield()
+		switch {
+		case err == nil:
+			idx++
+		case errors.Is(err, errCursorTaken):
+			// The user has the mouse; back off before trying again
+			if !sleepWithCancel(ctx, ms(cfg.Timing.CooldownMs)) {
+				return
+			}
+		}
+	}
+}
 
-		// Get button center in screen coordinates
-		sx, sy := platformClientToScreen(c[0]+btnW/2, c[1]+btnH/2)
+// cycle moves the button to corner i, glides the cursor onto it, performs
+// the corner's action (in click mode), taps the key if keys.with_mouse is
+// set and waits the random delay. It returns nil if it ran to the end;
+// otherwise it returns early, without clicking, with ctx.Err() or
+// errCursorTaken.
+func (e *engine) cycle(ctx context.Context, m mode, i int) error {
+	p, cfg := e.p, e.cfg
+	c := cfg.Window.corners()[i]
 
-		// Move cursor along Bézier curve to button center
-		moveCursorAlongCurve(sx, sy)
+	// Move button to next corner
+	p.MoveButton(c[0], c[1])
 
-		sleepWithCancel(200 * time.Millisecond)
-		if !active.Load() {
-			break
+	// Reinforce topmost
+	p.ReinforceTopmost()
+
+	if !sleepWithCancel(ctx, ms(cfg.Timing.SettleMs)) {
+		return ctx.Err()
+	}
+
+	// Get button center in screen coordinates
+	sx, sy := p.ClientToScreen(c[0]+cfg.Window.ButtonWidth/2, c[1]+cfg.Window.ButtonHeight/2)
+
+	// Move cursor along Bézier curve to button center
+	if err := moveCursorAlongCurve(ctx, p, e.glide, e.rng, sx, sy, e.failsafeTripped); err != nil {
+		return err
+	}
+
+	if !sleepWithCancel(ctx, ms(cfg.Timing.PreClickMs)) {
+		return ctx.Err()
+	}
+
+	// Act, but only if it would land on our button: another window may
+	// have popped up over it, or ours may have been dragged away
+	if a := cfg.actionAt(i); m == modeClick && a.Kind != actNone {
+		if !p.CursorOverButton() {
+			e.skippedClicks.Add(1)
+		} else if err := e.act(ctx, a); err != nil {
+			return err
 		}
+	}
+	if cfg.Keys.WithMouse {
+		p.TapKey(cfg.Keys.Key)
+	}
 
-		// Click
-		platformClick()
+	// Random delay between cycles
+	if !randomDelay(ctx, e.delay, e.rng) {
+		return ctx.Err()
+	}
+	return nil
+}
 
-		// Random delay 1-5 seconds
-		randomDelay()
-		idx++
+// keyCycle taps the keep-alive key and waits the random delay.
+func (e *engine) keyCycle(ctx context.Context) error {
+	e.p.TapKey(e.cfg.Keys.Key)
+	if !randomDelay(ctx, e.delay, e.rng) {
+		return ctx.Err()
 	}
+	return nil
 }
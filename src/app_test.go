@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastConfig is the default config with every wait cut to a few
+// milliseconds, so a test sees many cycles.
+func fastConfig() *config {
+	cfg := defaultConfig()
+	cfg.Timing = timingConfig{DelayMinMs: 5, DelayMaxMs: 5, DelayDist: distFixed}
+	cfg.Curve.FittsAMs, cfg.Curve.FittsBMs = 0, 10
+	cfg.Seed = 1
+	return cfg
+}
+
+// waitUntil polls cond until it holds or two seconds have passed.
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func lastEvent(t *testing.T, r *recordPlatform) recordedEvent {
+	t.Helper()
+	evs := r.Events()
+	if len(evs) == 0 {
+		t.Fatal("no events recorded")
+	}
+	return evs[len(evs)-1]
+}
+
+func TestAliveLoopVisitsCornersAndClicksCentre(t *testing.T) {
+	cfg := fastConfig()
+	r := newRecordPlatform(cfg.Window, 500, 300)
+	e := newEngine(r, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.aliveLoop(ctx)
+		close(done)
+	}()
+	waitUntil(t, "two rounds of clicks", func() bool { return len(r.EventsOf(evMouseDown)) >= 8 })
+	cancel()
+	<-done
+
+	if evs := r.Events(); evs[1].Kind != evPreventSleep {
+		t.Errorf("first loop event = %q, want %q", evs[1].Kind, evPreventSleep)
+	}
+	if ev := lastEvent(t, r); ev.Kind != evAllowSleep {
+		t.Errorf("last event = %q, want %q", ev.Kind, evAllowSleep)
+	}
+
+	corners := cfg.Window.corners()
+	var btn recordedEvent
+	clicks := 0
+	for _, ev := range r.Events() {
+		switch ev.Kind {
+		case evMoveButton:
+			if want := corners[clicks%4]; ev.X != want[0] || ev.Y != want[1] {
+				t.Fatalf("button move %d to %d,%d, want corner %d,%d", clicks, ev.X, ev.Y, want[0], want[1])
+			}
+			btn = ev
+		case evMouseDown:
+			wx := 500 + btn.X + cfg.Window.ButtonWidth/2
+			wy := 300 + btn.Y + cfg.Window.ButtonHeight/2
+			if ev.X != wx || ev.Y != wy || ev.Button != buttonLeft {
+				t.Fatalf("click %d at %d,%d (button %d), want left at %d,%d", clicks, ev.X, ev.Y, ev.Button, wx, wy)
+			}
+			clicks++
+		}
+	}
+	if n := len(r.EventsOf(evMouseUp)); n != clicks {
+		t.Errorf("%d mouse ups for %d downs", n, clicks)
+	}
+}
+
+func TestStopAllowsSleep(t *testing.T) {
+	cfg := fastConfig()
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+
+	e.Start()
+	waitUntil(t, "a click", func() bool { return len(r.EventsOf(evMouseDown)) > 0 })
+	e.Stop()
+	e.Wait()
+
+	if ev := lastEvent(t, r); ev.Kind != evAllowSleep {
+		t.Errorf("last event = %q, want %q", ev.Kind, evAllowSleep)
+	}
+	if e.Running() {
+		t.Error("engine still running after Stop")
+	}
+}
+
+func TestFailsafeStopsAndAllowsSleep(t *testing.T) {
+	cfg := fastConfig()
+	cfg.Timing.SettleMs = 200 // long enough for the watcher to see the corner
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+
+	e.Start()
+	waitUntil(t, "the button to move", func() bool { return len(r.EventsOf(evMoveButton)) > 0 })
+	r.SimulateUserMove(0, 0)
+	waitUntil(t, "the fail-safe", func() bool { return !e.Running() })
+	e.Wait()
+
+	if ev := lastEvent(t, r); ev.Kind != evAllowSleep {
+		t.Errorf("last event = %q, want %q", ev.Kind, evAllowSleep)
+	}
+	if n := len(r.EventsOf(evMouseDown)); n != 0 {
+		t.Errorf("%d clicks after the fail-safe tripped", n)
+	}
+}
+
+func TestYieldRestartsCornerAndAllowsSleep(t *testing.T) {
+	cfg := fastConfig()
+	cfg.Timing.IdleMs = 2 * int(idlePoll/time.Millisecond) // outlasts a poll
+	cfg.Timing.SettleMs = 500                              // the yield lands while the button settles
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+
+	e.Start()
+	waitUntil(t, "the button to move", func() bool { return len(r.EventsOf(evMoveButton)) > 0 })
+	r.SimulateUserInput()
+	waitUntil(t, "the cycle to restart", func() bool { return len(r.EventsOf(evMoveButton)) > 1 })
+	e.Stop()
+	e.Wait()
+
+	moves := r.EventsOf(evMoveButton)
+	if moves[0].X != moves[1].X || moves[0].Y != moves[1].Y {
+		t.Errorf("cycle after the yield went to %d,%d, want the same corner %d,%d", moves[1].X, moves[1].Y, moves[0].X, moves[0].Y)
+	}
+	if n := len(r.EventsOf(evMouseDown)); n != 0 {
+		t.Errorf("%d clicks in a cycle the user interrupted", n)
+	}
+	if ev := lastEvent(t, r); ev.Kind != evAllowSleep {
+		t.Errorf("last event = %q, want %q", ev.Kind, evAllowSleep)
+	}
+}
+
+func TestCoveredButtonSkipsClick(t *testing.T) {
+	cfg := fastConfig()
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	r.SimulateCovered(true)
+	e := newEngine(r, cfg)
+
+	e.Start()
+	waitUntil(t, "skipped clicks", func() bool { return e.skippedClicks.Load() >= 2 })
+	e.Stop()
+	e.Wait()
+
+	if n := len(r.EventsOf(evMouseDown)); n != 0 {
+		t.Errorf("%d clicks on a covered button", n)
+	}
+}
+
+func TestUserMoveAbortsGlideAndCoolsDown(t *testing.T) {
+	cfg := fastConfig()
+	cfg.Curve.FittsAMs = 300 // a glide long enough to interrupt
+	cfg.Timing.CooldownMs = 300
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+
+	e.Start()
+	waitUntil(t, "the glide to start", func() bool { return len(r.EventsOf(evSetCursor)) >= 3 })
+	r.SimulateUserMove(100, 900)
+	moved := time.Since(r.start)
+	waitUntil(t, "the next cycle", func() bool { return len(r.EventsOf(evMoveButton)) > 1 })
+	e.Stop()
+	e.Wait()
+
+	if n := len(r.EventsOf(evMouseDown)); n != 0 {
+		t.Errorf("%d clicks after the user took the mouse", n)
+	}
+	// The glide stops at the user's move: at most one step was on its way
+	late := 0
+	for _, ev := range r.EventsOf(evSetCursor) {
+		if ev.At > moved && ev.At < moved+ms(cfg.Timing.CooldownMs) {
+			late++
+		}
+	}
+	if late > 1 {
+		t.Errorf("%d glide steps after the user's move", late)
+	}
+	moves := r.EventsOf(evMoveButton)
+	if gap := moves[1].At - moved; gap < ms(cfg.Timing.CooldownMs) {
+		t.Errorf("next cycle %v after the user's move, want at least the %dms cooldown", gap, cfg.Timing.CooldownMs)
+	}
+	if moves[0].X != moves[1].X || moves[0].Y != moves[1].Y {
+		t.Errorf("cycle after the cooldown went to %d,%d, want the same corner %d,%d", moves[1].X, moves[1].Y, moves[0].X, moves[0].Y)
+	}
+}
+
+func TestKeyModeTapsWithoutGliding(t *testing.T) {
+	for _, k := range keys {
+		cfg := fastConfig()
+		cfg.Mode, cfg.Keys.Key = modeKey, k
+		r := newRecordPlatform(cfg.Window, 0, 0)
+		e := newEngine(r, cfg)
+
+		e.Start()
+		waitUntil(t, "three key taps", func() bool { return len(r.EventsOf(evKey)) >= 3 })
+		e.Stop()
+		e.Wait()
+
+		for _, ev := range r.EventsOf(evKey) {
+			if ev.Text != string(k) {
+				t.Errorf("tapped %q, want %q", ev.Text, k)
+			}
+		}
+		for _, kind := range []string{evSetCursor, evMoveButton, evMouseDown, evScroll} {
+			if n := len(r.EventsOf(kind)); n != 0 {
+				t.Errorf("key mode with %s: %d %q events", k, n, kind)
+			}
+		}
+	}
+}
+
+func TestWithMouseTapsAfterEachCycle(t *testing.T) {
+	for _, withMouse := range []bool{false, true} {
+		cfg := fastConfig()
+		cfg.Keys.WithMouse = withMouse
+		r := newRecordPlatform(cfg.Window, 0, 0)
+		e := newEngine(r, cfg)
+
+		e.Start()
+		waitUntil(t, "three clicks", func() bool { return len(r.EventsOf(evMouseDown)) >= 3 })
+		e.Stop()
+		e.Wait()
+
+		// One tap after every click, before the next cycle moves the button
+		var got string
+		for _, ev := range r.Events() {
+			switch ev.Kind {
+			case evMoveButton:
+				got += "b"
+			case evMouseUp:
+				got += "c"
+			case evKey:
+				got += "k"
+				if ev.Text != string(keyF15) {
+					t.Errorf("tapped %q, want the default %q", ev.Text, keyF15)
+				}
+			}
+		}
+		want := "bc"
+		if withMouse {
+			want = "bck"
+		}
+		if !strings.HasPrefix(got, strings.Repeat(want, 3)) {
+			t.Errorf("with_mouse %v: cycles %q, want %q each", withMouse, got, want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ── Configuration ───────────────────────────────────────────────────────────
+// Optional JSON file in the user config dir, e.g.
+//
+//   Windows: %AppData%\Clicky\config.json
+//   macOS:   ~/Library/Application Support/Clicky/config.json
+//   Linux:   ~/.config/Clicky/config.json
+//
+// Missing fields keep their defaults, so the file only needs what you change.
+
+type timingConfig struct {
+	DelayMinMs    int    `json:"delay_min_ms"` // random pause between cycles
+	DelayMaxMs    int    `json:"delay_max_ms"`
+	DelayDist     string `json:"delay_dist"`      // uniform, normal, lognormal, exponential or fixed
+	DelayMeanMs   int    `json:"delay_mean_ms"`   // 0 = middle of the range
+	DelayStddevMs int    `json:"delay_stddev_ms"` // 0 = a sixth of the range
+	SettleMs      int    `json:"settle_ms"`       // after moving the button
+	PreClickMs    int    `json:"pre_click_ms"`    // after the cursor arrives
+	IdleMs        int    `json:"idle_ms"`         // user inactivity before acting (0 = always act)
+	CooldownMs    int    `json:"cooldown_ms"`     // back-off after the user grabs the mouse mid-glide
+}
+
+type curveConfig struct {
+	Path      string  `json:"path"`        // quadratic, cubic, catmull-rom, straight or template
+	Waypoints int     `json:"waypoints"`   // catmull-rom points between start and end
+	Templates string  `json:"templates"`   // recorded strokes for the template path (default: next to the config)
+	Gravity   float64 `json:"gravity"`     // wind path: pull towards the target
+	Wind      float64 `json:"wind"`        // wind path: strength of the random push
+	MaxStepPx float64 `json:"max_step_px"` // wind path: top speed, px per step
+	Easing    string  `json:"easing"`      // none, linear, ease-in-out or minimum-jerk
+	FittsAMs  int     `json:"fitts_a_ms"`  // glide time = a + b·log2(distance/button width + 1)
+	FittsBMs  int     `json:"fitts_b_ms"`
+
+	OvershootChance float64 `json:"overshoot_chance"` // share of glides that overshoot and correct (0..1)
+	OvershootPx     int     `json:"overshoot_px"`     // how far past the target, at most
+	TremorChance    float64 `json:"tremor_chance"`    // share of glides with a slight wobble (0..1)
+	TremorPx        int     `json:"tremor_px"`        // wobble amplitude
+	Steps           int     `json:"steps"`            // cursor positions per glide
+	StepMs          int     `json:"step_ms"`          // pause between positions
+	TolerancePx     int     `json:"tolerance_px"`     // cursor drift that counts as the user moving it
+}
+
+type windowConfig struct {
+	Width        int `json:"width"` // client area
+	Height       int `json:"height"`
+	ButtonWidth  int `json:"button_width"`
+	ButtonHeight int `json:"button_height"`
+	Padding      int `json:"padding"` // button inset at each corner
+}
+
+type keysConfig struct {
+	Key       key  `json:"key"`        // f15 or shift
+	WithMouse bool `json:"with_mouse"` // also tap it after every mouse cycle
+}
+
+type config struct {
+	Timing   timingConfig   `json:"timing"`
+	Curve    curveConfig    `json:"curve"`
+	Window   windowConfig   `json:"window"`
+	Mode     mode           `json:"mode"` // click, move, key or sleep
+	Keys     keysConfig     `json:"keys"`
+	Actions  []action       `json:"actions"` // click mode: one for every corner, or one per corner
+	Failsafe failsafeConfig `json:"failsafe"`
+	Schedule scheduleConfig `json:"schedule"`
+	Timer    timerConfig    `json:"timer"`
+	Seed     int64          `json:"seed"` // random seed for delays and curves (0 = new each run)
+}
+
+func defaultConfig() *config {
+	return &config{
+		Timing:   timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: distUniform, SettleMs: 400, PreClickMs: 200, IdleMs: 10000, CooldownMs: 5000},
+		Curve:    curveConfig{Path: pathQuadratic, Waypoints: 2, Gravity: 9, Wind: 3, MaxStepPx: 15, Easing: easeMinimumJerk, FittsAMs: 50, FittsBMs: 150, OvershootPx: 20, TremorPx: 1, Steps: 25, StepMs: 4, TolerancePx: 4},
+		Window:   windowConfig{Width: 300, Height: 300, ButtonWidth: 80, ButtonHeight: 30, Padding: 10},
+		Mode:     modeClick,
+		Keys:     keysConfig{Key: keyF15},
+		Actions:  []action{{Kind: actLeft}},
+		Failsafe: failsafeConfig{Corner: "top-left", MarginPx: 2},
+		Timer:    timerConfig{PresetsMin: []int{30, 60, 120, 240, 480}},
+	}
+}
+
+func ms(v int) time.Duration { return time.Duration(v) * time.Millisecond }
+
+// defaultConfigPath returns the config file location, or "" if the user
+// config dir is unknown.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "Clicky", "config.json")
+}
+
+// loadConfig reads path over the defaults. A missing file is not an error.
+func loadConfig(path string) (*config, error) {
+	cfg := defaultConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return defaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *config) validate() error {
+	if !c.Mode.valid() {
+		return fmt.Errorf("mode must be one of %v, got %q", modes, c.Mode)
+	}
+	if !c.Keys.Key.valid() {
+		return fmt.Errorf("keys: key must be one of %v, got %q", keys, c.Keys.Key)
+	}
+	if err := validateActions(c.Actions); err != nil {
+		return err
+	}
+	if err := c.Failsafe.validate(); err != nil {
+		return err
+	}
+	if err := c.Schedule.validate(); err != nil {
+		return err
+	}
+	if err := c.Timer.validate(); err != nil {
+		return err
+	}
+
+	t := c.Timing
+	switch {
+	case t.DelayMinMs < 0 || t.DelayMaxMs < t.DelayMinMs:
+		return fmt.Errorf("timing: need 0 <= delay_min_ms <= delay_max_ms, got %d..%d", t.DelayMinMs, t.DelayMaxMs)
+	case t.DelayMaxMs > 3600_000:
+		return fmt.Errorf("timing: delay_max_ms %d is over an hour", t.DelayMaxMs)
+	case t.SettleMs < 0 || t.PreClickMs < 0 || t.IdleMs < 0 || t.CooldownMs < 0:
+		return errors.New("timing: settle_ms, pre_click_ms, idle_ms and cooldown_ms must not be negative")
+	}
+	if _, err := newDelayDist(t); err != nil {
+		return err
+	}
+
+	if c.Curve.Steps < 1 || c.Curve.Steps > 1000 {
+		return fmt.Errorf("curve: steps must be 1..1000, got %d", c.Curve.Steps)
+	}
+	if c.Curve.StepMs < 0 || c.Curve.StepMs > 1000 {
+		return fmt.Errorf("curve: step_ms must be 0..1000, got %d", c.Curve.StepMs)
+	}
+	if c.Curve.TolerancePx < 0 {
+		return errors.New("curve: tolerance_px must not be negative")
+	}
+	if c.Curve.FittsAMs < 0 || c.Curve.FittsBMs < 0 {
+		return errors.New("curve: fitts_a_ms and fitts_b_ms must not be negative")
+	}
+	if cc := c.Curve; cc.OvershootChance < 0 || cc.OvershootChance > 1 || cc.TremorChance < 0 || cc.TremorChance > 1 {
+		return errors.New("curve: overshoot_chance and tremor_chance must be 0..1")
+	}
+	if cc := c.Curve; cc.OvershootPx < 0 || cc.OvershootPx > 200 || cc.TremorPx < 0 || cc.TremorPx > 20 {
+		return fmt.Errorf("curve: need overshoot_px 0..200 and tremor_px 0..20, got %d and %d", cc.OvershootPx, cc.TremorPx)
+	}
+	if _, err := newGlide(c.Curve, c.Window.ButtonWidth); err != nil {
+		return err
+	}
+
+	w := c.Window
+	switch {
+	case w.Width < 100 || w.Height < 100 || w.Width > 4000 || w.Height > 4000:
+		return fmt.Errorf("window: size must be 100..4000, got %dx%d", w.Width, w.Height)
+	case w.ButtonWidth < 20 || w.ButtonHeight < 16:
+		return fmt.Errorf("window: button too small (%dx%d)", w.ButtonWidth, w.ButtonHeight)
+	case w.Padding < 0:
+		return errors.New("window: padding must not be negative")
+	case w.ButtonWidth+2*w.Padding > w.Width || w.ButtonHeight+2*w.Padding > w.Height:
+		return errors.New("window: button plus padding does not fit the window")
+	case w.modeRect()[2] < minModeWidth:
+		return errors.New("window: too narrow for the mode selector between the top corners")
+	case w.statusRect()[1] < w.Padding+w.ButtonHeight || w.timerRect()[1]+w.ButtonHeight > w.Height-w.Padding-w.ButtonHeight:
+		return errors.New("window: too short for the status line and timer between the corners")
+	}
+	return nil
+}
+
+const minModeWidth = 60
+
+// modeRect returns x, y, width, height of the mode selector: top edge,
+// between the two top button corners.
+func (w windowConfig) modeRect() [4]int {
+	x := w.ButtonWidth + 2*w.Padding
+	return [4]int{x, w.Padding, w.Width - 2*x, w.ButtonHeight}
+}
+
+// statusRect and timerRect return x, y, width, height of the status line and
+// the timer selector, just above and below the idle button in the middle.
+func (w windowConfig) statusRect() [4]int {
+	return [4]int{0, (w.Height-w.ButtonHeight)/2 - w.Padding - w.ButtonHeight, w.Width, w.ButtonHeight}
+}
+
+func (w windowConfig) timerRect() [4]int {
+	mr := w.modeRect()
+	return [4]int{mr[0], (w.Height+w.ButtonHeight)/2 + w.Padding, mr[2], w.ButtonHeight}
+}
+
+// corners returns the button positions inside the client area.
+func (w windowConfig) corners() [4][2]int {
+	right := w.Width - w.ButtonWidth - w.Padding
+	bottom := w.Height - w.ButtonHeight - w.Padding
+	return [4][2]int{
+		{w.Padding, w.Padding}, // top-left
+		{right, w.Padding},     // top-right
+		{right, bottom},        // bottom-right
+		{w.Padding, bottom},    // bottom-left
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateRejects(t *testing.T) {
+	if err := defaultConfig().validate(); err != nil {
+		t.Fatalf("defaults rejected: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		set  func(c *config)
+		want string // start of the error
+	}{
+		{"mode", func(c *config) { c.Mode = "dance" }, "mode must be one of"},
+		{"key", func(c *config) { c.Keys.Key = "f13" }, "keys: key must be one of"},
+		{"two actions", func(c *config) { c.Actions = []action{{Kind: actLeft}, {Kind: actRight}} }, "actions: need 1 action or 4"},
+		{"action kind", func(c *config) { c.Actions = []action{{Kind: "triple"}} }, "actions: kind must be one of"},
+		{"scroll without ticks", func(c *config) { c.Actions = []action{{Kind: actScroll}} }, "actions: scroll needs ticks"},
+		{"long hold", func(c *config) { c.Actions = []action{{Kind: actLeft, HoldMs: maxHoldMs + 1}} }, "actions: hold_ms must be"},
+		{"fail-safe corner", func(c *config) { c.Failsafe.Corner = "middle" }, "failsafe: corner must be one of"},
+		{"fail-safe margin", func(c *config) { c.Failsafe.MarginPx = -1 }, "failsafe: margin_px must not be negative"},
+		{"timer for and until", func(c *config) { c.Timer.ForMin, c.Timer.Until = 30, "17:00" }, "timer: set for_min or until"},
+		{"timer until", func(c *config) { c.Timer.Until = "25:00" }, "timer: until:"},
+		{"timer presets", func(c *config) { c.Timer.PresetsMin = []int{60, 30} }, "timer: presets_min must be positive and ascending"},
+		{"delay range", func(c *config) { c.Timing.DelayMinMs, c.Timing.DelayMaxMs = 5000, 1000 }, "timing: need 0 <= delay_min_ms <= delay_max_ms"},
+		{"negative delay", func(c *config) { c.Timing.DelayMinMs = -1 }, "timing: need 0 <= delay_min_ms"},
+		{"delay over an hour", func(c *config) { c.Timing.DelayMaxMs = 3600_001 }, "timing: delay_max_ms 3600001 is over an hour"},
+		{"negative idle", func(c *config) { c.Timing.IdleMs = -1 }, "timing: settle_ms, pre_click_ms, idle_ms and cooldown_ms"},
+		{"negative cooldown", func(c *config) { c.Timing.CooldownMs = -1 }, "timing: settle_ms, pre_click_ms, idle_ms and cooldown_ms"},
+		{"delay dist", func(c *config) { c.Timing.DelayDist = "poisson" }, "timing: delay_dist"},
+		{"no steps", func(c *config) { c.Curve.Steps = 0 }, "curve: steps must be 1..1000"},
+		{"slow steps", func(c *config) { c.Curve.StepMs = 1001 }, "curve: step_ms must be 0..1000"},
+		{"tolerance", func(c *config) { c.Curve.TolerancePx = -1 }, "curve: tolerance_px"},
+		{"fitts", func(c *config) { c.Curve.FittsBMs = -1 }, "curve: fitts_a_ms and fitts_b_ms"},
+		{"overshoot chance", func(c *config) { c.Curve.OvershootChance = 1.5 }, "curve: overshoot_chance and tremor_chance"},
+		{"tremor chance", func(c *config) { c.Curve.TremorChance = -0.1 }, "curve: overshoot_chance and tremor_chance"},
+		{"overshoot px", func(c *config) { c.Curve.OvershootPx = 201 }, "curve: need overshoot_px 0..200"},
+		{"tremor px", func(c *config) { c.Curve.TremorPx = 21 }, "curve: need overshoot_px 0..200"},
+		{"path", func(c *config) { c.Curve.Path = "spiral" }, "curve: path must be one of"},
+		{"waypoints", func(c *config) { c.Curve.Path, c.Curve.Waypoints = pathCatmullRom, 11 }, "curve: waypoints must be 1..10"},
+		{"wind over gravity", func(c *config) { c.Curve.Path, c.Curve.Wind = pathWind, 28 }, "curve: wind needs"},
+		{"weak gravity", func(c *config) { c.Curve.Path, c.Curve.Gravity, c.Curve.Wind = pathWind, 0.5, 0 }, "curve: wind needs"},
+		{"wind max step", func(c *config) { c.Curve.Path, c.Curve.MaxStepPx = pathWind, 2 }, "curve: wind needs"},
+		{"easing", func(c *config) { c.Curve.Easing = "bounce" }, "curve: easing must be one of"},
+		{"small window", func(c *config) { c.Window.Width = 99 }, "window: size must be 100..4000"},
+		{"huge window", func(c *config) { c.Window.Height = 4001 }, "window: size must be 100..4000"},
+		{"small button", func(c *config) { c.Window.ButtonHeight = 15 }, "window: button too small"},
+		{"padding", func(c *config) { c.Window.Padding = -1 }, "window: padding must not be negative"},
+		{"button does not fit", func(c *config) { c.Window.Width, c.Window.ButtonWidth = 150, 140 }, "window: button plus padding does not fit"},
+		{"no room for the mode", func(c *config) { c.Window.Width = 259 }, "window: too narrow for the mode selector"},
+		{"no room for the timer", func(c *config) { c.Window.Height = 150 }, "window: too short for the status line"},
+	} {
+		c := defaultConfig()
+		tc.set(c)
+		if err := c.validate(); err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("%s: error %v, want %q…", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	load := func(content string) (*config, error) {
+		p := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return loadConfig(p)
+	}
+
+	// Fields not in the file keep their defaults
+	cfg, err := load(`{"timing": {"delay_min_ms": 2000}, "mode": "move"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timing.DelayMinMs != 2000 || cfg.Mode != modeMove || cfg.Timing.DelayMaxMs != 5000 || cfg.Curve.Steps != 25 {
+		t.Errorf("loaded %+v", cfg)
+	}
+
+	// A bad file is reported and the defaults used instead
+	for _, content := range []string{`{"timing": {"delay_min": 2000}}`, `{"mode": "dance"}`, `{"mode": `} {
+		cfg, err := load(content)
+		if err == nil {
+			t.Errorf("%s: accepted", content)
+		}
+		if cfg.Mode != modeClick || cfg.Timing.DelayMinMs != 1000 {
+			t.Errorf("%s: not the defaults: %+v", content, cfg)
+		}
+	}
+
+	if _, err := loadConfig(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("missing file: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+#include <dbus/dbus.h>
+
+#include "dbus_linux.h"
+
+#define CALL_TIMEOUT_MS 2000
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// busGet returns the shared connection for the given bus. libdbus honours
+// DBUS_SYSTEM_BUS_ADDRESS / DBUS_SESSION_BUS_ADDRESS, so a private
+// dbus-daemon can stand in for the real services.
+static DBusConnection *busGet(DBusBusType type) {
+    static int threadsReady = 0;
+    if (!threadsReady) {
+        dbus_threads_init_default();
+        threadsReady = 1;
+    }
+
+    DBusError err;
+    dbus_error_init(&err);
+    DBusConnection *conn = dbus_bus_get(type, &err);
+    dbus_error_free(&err);
+    if (conn) {
+        // A bus that went away leaves a dead shared connection behind; let
+        // libdbus notice the hangup and drop it, then connect again
+        dbus_connection_read_write(conn, 0);
+        if (!dbus_connection_get_is_connected(conn)) {
+            while (dbus_connection_dispatch(conn) == DBUS_DISPATCH_DATA_REMAINS) {
+            }
+            dbus_connection_unref(conn);
+            dbus_error_init(&err);
+            conn = dbus_bus_get(type, &err);
+            dbus_error_free(&err);
+        }
+    }
+    if (conn) {
+        // Calls come from the alive goroutine; never kill the process
+        dbus_connection_set_exit_on_disconnect(conn, FALSE);
+    }
+    return conn;
+}
+
+// callBlocking sends msg, consumes it and returns the reply (or NULL).
+static DBusMessage *callBlocking(DBusConnection *conn, DBusMessage *msg) {
+    DBusError err;
+    dbus_error_init(&err);
+    DBusMessage *reply = dbus_connection_send_with_reply_and_block(conn, msg, CALL_TIMEOUT_MS, &err);
+    dbus_message_unref(msg);
+    dbus_error_free(&err);
+    return reply;
+}
+
+// ── C functions called from Go ──────────────────────────────────────────────
+
+// dbusLogindInhibit takes a systemd-logind "block" inhibitor lock and returns
+// its file descriptor; the lock is held until the descriptor is closed.
+int dbusLogindInhibit(const char *what, const char *who, const char *why) {
+    DBusConnection *conn = busGet(DBUS_BUS_SYSTEM);
+    if (!conn) {
+        return -1;
+    }
+
+    DBusMessage *msg = dbus_message_new_method_call(
+        "org.freedesktop.login1",
+        "/org/freedesktop/login1",
+        "org.freedesktop.login1.Manager",
+        "Inhibit");
+    const char *mode = "block";
+    dbus_message_append_args(msg,
+        DBUS_TYPE_STRING, &what,
+        DBUS_TYPE_STRING, &who,
+        DBUS_TYPE_STRING, &why,
+        DBUS_TYPE_STRING, &mode,
+        DBUS_TYPE_INVALID);
+
+    int fd = -1;
+    DBusMessage *reply = callBlocking(conn, msg);
+    if (reply) {
+        DBusError err;
+        dbus_error_init(&err);
+        if (!dbus_message_get_args(reply, &err, DBUS_TYPE_UNIX_FD, &fd, DBUS_TYPE_INVALID)) {
+            fd = -1;
+        }
+        dbus_error_free(&err);
+        dbus_message_unref(reply);
+    }
+    dbus_connection_unref(conn);
+    return fd;
+}
+
+// dbusScreenSaverInhibit calls org.freedesktop.ScreenSaver.Inhibit. The
+// service drops the inhibition if our connection goes away, so the shared
+// session connection is kept open for the life of the process.
+int dbusScreenSaverInhibit(const char *app, const char *reason, unsigned int *outCookie) {
+    DBusConnection *conn = busGet(DBUS_BUS_SESSION);
+    if (!conn) {
+        return 0;
+    }
+
+    DBusMessage *msg = dbus_message_new_method_call(
+        "org.freedesktop.ScreenSaver",
+        "/org/freedesktop/ScreenSaver",
+        "org.freedesktop.ScreenSaver",
+        "Inhibit");
+    dbus_message_append_args(msg,
+        DBUS_TYPE_STRING, &app,
+        DBUS_TYPE_STRING, &reason,
+        DBUS_TYPE_INVALID);
+
+    int ok = 0;
+    DBusMessage *reply = callBlocking(conn, msg);
+    if (reply) {
+        DBusError err;
+        dbus_error_init(&err);
+        dbus_uint32_t cookie = 0;
+        if (dbus_message_get_args(reply, &err, DBUS_TYPE_UINT32, &cookie, DBUS_TYPE_INVALID)) {
+            *outCookie = cookie;
+            ok = 1;
+        }
+        dbus_error_free(&err);
+        dbus_message_unref(reply);
+    }
+    dbus_connection_unref(conn);
+    return ok;
+}
+
+void dbusScreenSaverUnInhibit(unsigned int cookie) {
+    DBusConnection *conn = busGet(DBUS_BUS_SESSION);
+    if (!conn) {
+        return;
+    }
+
+    DBusMessage *msg = dbus_message_new_method_call(
+        "org.freedesktop.ScreenSaver",
+        "/org/freedesktop/ScreenSaver",
+        "org.freedesktop.ScreenSaver",
+        "UnInhibit");
+    dbus_uint32_t c = cookie;
+    dbus_message_append_args(msg, DBUS_TYPE_UINT32, &c, DBUS_TYPE_INVALID);
+
+    DBusMessage *reply = callBlocking(conn, msg);
+    if (reply) {
+        dbus_message_unref(reply);
+    }
+    dbus_connection_unref(conn);
+}
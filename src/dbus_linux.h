@@ -0,0 +1,8 @@
+#ifndef DBUS_LINUX_H
+#define DBUS_LINUX_H
+
+int  dbusLogindInhibit(const char *what, const char *who, const char *why);
+int  dbusScreenSaverInhibit(const char *app, const char *reason, unsigned int *outCookie);
+void dbusScreenSaverUnInhibit(unsigned int cookie);
+
+#endif
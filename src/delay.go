@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ── Delay distributions ─────────────────────────────────────────────────────
+// The pause between cycles is drawn from a configurable distribution. Every
+// distribution is clamped to [delay_min_ms, delay_max_ms]; the mean and
+// standard deviation default to the middle of that range and a sixth of its
+// width, so about 99.7% of normal draws land inside it unclamped.
+
+const (
+	distUniform     = "uniform"     // flat over [min, max]
+	distNormal      = "normal"      // bell curve around the mean, clamped
+	distLogNormal   = "lognormal"   // skewed: mostly short, now and then long
+	distExponential = "exponential" // memoryless, like a Poisson process
+	distFixed       = "fixed"       // always the mean
+)
+
+var delayDists = []string{distUniform, distNormal, distLogNormal, distExponential, distFixed}
+
+// randSource is the part of *rand.Rand the distributions draw from.
+type randSource interface {
+	Intn(n int) int
+	Float64() float64
+	NormFloat64() float64
+	ExpFloat64() float64
+}
+
+// delayDist draws one pause between cycles.
+type delayDist interface {
+	Sample(r randSource) time.Duration
+}
+
+type uniformDelay struct{ minMs, maxMs int }
+
+func (d uniformDelay) Sample(r randSource) time.Duration {
+	return ms(d.minMs + r.Intn(d.maxMs-d.minMs+1))
+}
+
+type normalDelay struct{ mean, stddev, min, max float64 }
+
+func (d normalDelay) Sample(r randSource) time.Duration {
+	return clampMs(d.mean+r.NormFloat64()*d.stddev, d.min, d.max)
+}
+
+// logNormalDelay is parameterised by the mean and standard deviation of the
+// delay itself; mu and sigma are those of its logarithm.
+type logNormalDelay struct{ mu, sigma, min, max float64 }
+
+func (d logNormalDelay) Sample(r randSource) time.Duration {
+	return clampMs(math.Exp(d.mu+r.NormFloat64()*d.sigma), d.min, d.max)
+}
+
+// expDelay waits min plus an exponential tail with mean (mean - min). Draws
+// past max are clamped to it, which pulls the mean below the configured one:
+// with the default 1–5 s it is about 2.73 s, not 3 s.
+type expDelay struct{ min, tail, max float64 }
+
+func (d expDelay) Sample(r randSource) time.Duration {
+	return clampMs(d.min+r.ExpFloat64()*d.tail, d.min, d.max)
+}
+
+type fixedDelay time.Duration
+
+func (d fixedDelay) Sample(randSource) time.Duration { return time.Duration(d) }
+
+func clampMs(v, lo, hi float64) time.Duration {
+	return time.Duration(math.Round(math.Max(lo, math.Min(hi, v))) * float64(time.Millisecond))
+}
+
+// newDelayDist builds the distribution t asks for. It expects delay_min_ms
+// and delay_max_ms to be valid already.
+func newDelayDist(t timingConfig) (delayDist, error) {
+	lo, hi := float64(t.DelayMinMs), float64(t.DelayMaxMs)
+	mean, stddev := (lo+hi)/2, (hi-lo)/6
+	if t.DelayMeanMs != 0 {
+		mean = float64(t.DelayMeanMs)
+	}
+	if t.DelayStddevMs != 0 {
+		stddev = float64(t.DelayStddevMs)
+	}
+	if mean < lo || mean > hi {
+		return nil, fmt.Errorf("timing: delay_mean_ms %v is outside delay_min_ms..delay_max_ms", mean)
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("timing: delay_stddev_ms must not be negative")
+	}
+
+	switch t.DelayDist {
+	case distUniform, "":
+		return uniformDelay{t.DelayMinMs, t.DelayMaxMs}, nil
+	case distNormal:
+		return normalDelay{mean, stddev, lo, hi}, nil
+	case distLogNormal:
+		if mean <= 0 {
+			return nil, fmt.Errorf("timing: lognormal needs a positive delay mean")
+		}
+		sigma2 := math.Log(1 + stddev*stddev/(mean*mean))
+		return logNormalDelay{math.Log(mean) - sigma2/2, math.Sqrt(sigma2), lo, hi}, nil
+	case distExponential:
+		return expDelay{lo, mean - lo, hi}, nil
+	case distFixed:
+		return fixedDelay(math.Round(mean) * float64(time.Millisecond)), nil
+	}
+	return nil, fmt.Errorf("timing: delay_dist must be one of %v, got %q", delayDists, t.DelayDist)
+}
+
+// randomDelay waits one draw from d, with cancellation support.
+func randomDelay(ctx context.Context, d delayDist, r randSource) bool {
+	return sleepWithCancel(ctx, d.Sample(r))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestDelayDists draws from every distribution and checks the bounds and
+// the first two moments. Expected moments are those of the distribution
+// after clamping to [delay_min_ms, delay_max_ms], found by numerical
+// integration: the clamp shortens the exponential tail a lot (mean 2.73 s,
+// not 3 s) and the others a little.
+func TestDelayDists(t *testing.T) {
+	const draws = 100000
+	for _, tc := range []struct {
+		name     string
+		timing   timingConfig
+		mean     float64 // ms
+		variance float64 // ms²
+	}{
+		{"uniform", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: distUniform}, 3000, 1334000},
+		{"default is uniform", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000}, 3000, 1334000},
+		{"normal", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: distNormal}, 3000, 442225},
+		{"lognormal", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: distLogNormal}, 2997, 430808},
+		{"exponential", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: distExponential}, 2729, 1761373},
+		{"exponential, short mean", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayMeanMs: 1500, DelayDist: distExponential}, 1500, 248658},
+		{"normal, own mean and stddev", timingConfig{DelayMinMs: 0, DelayMaxMs: 10000, DelayMeanMs: 2000, DelayStddevMs: 100, DelayDist: distNormal}, 2000, 10000},
+		{"fixed", timingConfig{DelayMinMs: 1000, DelayMaxMs: 5000, DelayMeanMs: 1234, DelayDist: distFixed}, 1234, 0},
+	} {
+		d, err := newDelayDist(tc.timing)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		r := rand.New(rand.NewSource(1))
+		lo, hi := ms(tc.timing.DelayMinMs), ms(tc.timing.DelayMaxMs)
+		var sum, sumSq float64
+		for i := 0; i < draws; i++ {
+			v := d.Sample(r)
+			if v < lo || v > hi {
+				t.Fatalf("%s: drew %v, outside [%v, %v]", tc.name, v, lo, hi)
+			}
+			x := float64(v) / float64(time.Millisecond)
+			sum += x
+			sumSq += x * x
+		}
+		mean := sum / draws
+		variance := sumSq/draws - mean*mean
+		if math.Abs(mean-tc.mean) > 0.01*tc.mean {
+			t.Errorf("%s: mean %.0f ms, want %.0f ms", tc.name, mean, tc.mean)
+		}
+		if math.Abs(variance-tc.variance) > 0.03*tc.variance+1 {
+			t.Errorf("%s: variance %.0f ms², want %.0f ms²", tc.name, variance, tc.variance)
+		}
+	}
+}
+
+func TestDelayDistErrors(t *testing.T) {
+	for _, tm := range []timingConfig{
+		{DelayMinMs: 1000, DelayMaxMs: 5000, DelayDist: "poisson"},
+		{DelayMinMs: 1000, DelayMaxMs: 5000, DelayMeanMs: 6000, DelayDist: distNormal},
+		{DelayMinMs: 1000, DelayMaxMs: 5000, DelayStddevMs: -1, DelayDist: distNormal},
+		{DelayMinMs: 0, DelayMaxMs: 0, DelayDist: distLogNormal},
+	} {
+		if _, err := newDelayDist(tm); err == nil {
+			t.Errorf("newDelayDist(%+v) accepted", tm)
+		}
+	}
+}
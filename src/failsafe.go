@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ── Fail-safe corner ────────────────────────────────────────────────────────
+// Slamming the physical cursor into the configured screen corner stops the
+// engine at once: the loop is cancelled, the sleep assertion released and
+// the button shown idle. The corner is checked before every glide step and
+// polled during every wait.
+
+const failsafePoll = 50 * time.Millisecond
+
+type failsafeConfig struct {
+	Corner   string `json:"corner"`    // top-left, top-right, bottom-left, bottom-right or off
+	MarginPx int    `json:"margin_px"` // distance from the corner that still counts
+}
+
+var failsafeCorners = []string{"off", "top-left", "top-right", "bottom-left", "bottom-right"}
+
+func (f failsafeConfig) validate() error {
+	for _, c := range failsafeCorners {
+		if f.Corner == c {
+			if f.MarginPx < 0 {
+				return fmt.Errorf("failsafe: margin_px must not be negative")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("failsafe: corner must be one of %v, got %q", failsafeCorners, f.Corner)
+}
+
+// cursorSource is the part of a Platform the fail-safe needs.
+type cursorSource interface {
+	GetCursorPos() (int, int)
+	ScreenSize() (int, int)
+}
+
+// inFailsafeCorner reports whether the cursor is in the configured corner
+// of the main screen. Positions off it, on another monitor, never are.
+func inFailsafeCorner(src cursorSource, f failsafeConfig) bool {
+	if f.Corner == "off" || f.Corner == "" {
+		return false
+	}
+	x, y := src.GetCursorPos()
+	w, h := src.ScreenSize()
+	m := f.MarginPx
+
+	left := 0 <= x && x <= m
+	right := w-1-m <= x && x <= w-1
+	top := 0 <= y && y <= m
+	bottom := h-1-m <= y && y <= h-1
+
+	switch f.Corner {
+	case "top-left":
+		return top && left
+	case "top-right":
+		return top && right
+	case "bottom-left":
+		return bottom && left
+	case "bottom-right":
+		return bottom && right
+	}
+	return false
+}
+
+// failsafeTripped checks the corner and, if the cursor is in it, stops the
+// engine and calls onFailsafe. It reports whether it did.
+func (e *engine) failsafeTripped() bool {
+	if !inFailsafeCorner(e.p, e.cfg.Failsafe) {
+		return false
+	}
+	e.Stop()
+	if e.onFailsafe != nil {
+		e.onFailsafe()
+	}
+	return true
+}
+
+// watchFailsafe polls the corner until ctx is done, so waits are covered too.
+func (e *engine) watchFailsafe(ctx context.Context) {
+	if e.cfg.Failsafe.Corner == "off" {
+		return
+	}
+	t := time.NewTicker(failsafePoll)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if e.failsafeTripped() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// fakeCursor is a cursorSource on a 1920x1080 screen.
+type fakeCursor struct{ x, y int }
+
+func (c fakeCursor) GetCursorPos() (int, int) { return c.x, c.y }
+func (c fakeCursor) ScreenSize() (int, int)   { return 1920, 1080 }
+
+func TestInFailsafeCorner(t *testing.T) {
+	for _, tc := range []struct {
+		corner string
+		margin int
+		x, y   int
+		want   bool
+	}{
+		{"top-left", 0, 0, 0, true},
+		{"top-left", 0, 1, 0, false},
+		{"top-left", 2, 2, 2, true},
+		{"top-left", 2, 3, 2, false},
+		{"top-left", 2, 2, 3, false},
+		{"top-right", 0, 1919, 0, true},
+		{"top-right", 2, 1917, 2, true},
+		{"top-right", 2, 1916, 0, false},
+		{"bottom-left", 0, 0, 1079, true},
+		{"bottom-left", 2, 2, 1077, true},
+		{"bottom-left", 2, 0, 1076, false},
+		{"bottom-right", 0, 1919, 1079, true},
+		{"bottom-right", 5, 1914, 1074, true},
+		{"bottom-right", 5, 1913, 1079, false},
+		{"top-left", 2, 1919, 1079, false}, // the wrong corner
+		{"bottom-right", 2, 0, 0, false},
+		// Other monitors: left of, right of and below the main screen
+		{"top-left", 2, -1, 0, false},
+		{"top-left", 2, -1920, -500, false},
+		{"bottom-left", 2, -3, 1079, false},
+		{"top-right", 2, 1920, 0, false},
+		{"top-right", 2, 2500, 1, false},
+		{"bottom-right", 2, 3839, 1079, false},
+		{"bottom-right", 2, 1919, 1080, false},
+		{"bottom-left", 2, 0, 2159, false},
+		{"off", 2, 0, 0, false},
+		{"", 2, 0, 0, false},
+	} {
+		f := failsafeConfig{Corner: tc.corner, MarginPx: tc.margin}
+		if got := inFailsafeCorner(fakeCursor{tc.x, tc.y}, f); got != tc.want {
+			t.Errorf("%s margin %d at %d,%d: got %v, want %v", tc.corner, tc.margin, tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestFailsafeTripped(t *testing.T) {
+	cfg := fastConfig()
+	cfg.Failsafe = failsafeConfig{Corner: "bottom-right", MarginPx: 2}
+	cfg.Mode = modeSleep // running, but leaves the cursor alone
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+	var hooked atomic.Int32 // the watcher may trip it as well
+	e.onFailsafe = func() { hooked.Add(1) }
+	e.Start()
+	defer e.Wait()
+	defer e.Stop()
+
+	if e.failsafeTripped() || !e.Running() {
+		t.Fatal("fail-safe tripped with the cursor mid-screen")
+	}
+	r.SimulateUserMove(0, 0)
+	if e.failsafeTripped() || !e.Running() {
+		t.Fatal("fail-safe tripped in the wrong corner")
+	}
+	r.SimulateUserMove(1918, 1077)
+	if !e.failsafeTripped() {
+		t.Fatal("fail-safe not tripped in its corner")
+	}
+	if e.Running() {
+		t.Error("engine still running after the fail-safe tripped")
+	}
+	if hooked.Load() == 0 {
+		t.Error("onFailsafe not called")
+	}
+}
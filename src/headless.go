@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ── Headless mode ───────────────────────────────────────────────────────────
+// headlessPlatform runs the engine without a window. Cursor and sleep calls
+// go to the real backend; window calls are no-ops and the "client area" is
+// anchored where the cursor was at startup. There is no button of ours to
+// click or scroll over, so mouse buttons and the wheel are no-ops too. Run
+// blocks until Quit or SIGINT/SIGTERM.
+
+type headlessPlatform struct {
+	Platform // real backend; its Run is never called
+
+	originX, originY int
+
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newHeadlessPlatform(p Platform, win windowConfig) *headlessPlatform {
+	x, y := p.GetCursorPos()
+	return &headlessPlatform{
+		Platform: p,
+		originX:  x - win.Width/2,
+		originY:  y - win.Height/2,
+		quit:     make(chan struct{}),
+	}
+}
+
+func (h *headlessPlatform) Run() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	if onWindowReady != nil {
+		onWindowReady()
+	}
+
+	select {
+	case <-h.quit:
+	case <-sig:
+	}
+}
+
+func (h *headlessPlatform) MouseDown(mouseButton, int) {}
+func (h *headlessPlatform) MouseUp(mouseButton, int)   {}
+func (h *headlessPlatform) Scroll(int)                 {}
+func (h *headlessPlatform) CursorOverButton() bool     { return false }
+func (h *headlessPlatform) MoveButton(x, y int)        {}
+func (h *headlessPlatform) SetButtonActive(bool)       {}
+func (h *headlessPlatform) SetModeLabel(string)        {}
+func (h *headlessPlatform) SetStatus(string)           {}
+func (h *headlessPlatform) SetTimerLabel(string)       {}
+func (h *headlessPlatform) ReinforceTopmost()          {}
+
+func (h *headlessPlatform) ClientToScreen(x, y int) (int, int) {
+	return h.originX + x, h.originY + y
+}
+
+func (h *headlessPlatform) Quit() {
+	h.quitOnce.Do(func() { close(h.quit) })
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ── User idle detection ─────────────────────────────────────────────────────
+// The engine only moves the mouse after cfg.Timing.IdleMs of user inactivity
+// and gives up the current cycle as soon as the user touches the mouse or
+// keyboard. The OS idle timer counts our own synthetic input too, so input
+// that lands right after one of our moves or clicks is treated as ours.
+
+const (
+	idlePoll       = 100 * time.Millisecond
+	syntheticSlack = 100 * time.Millisecond // OS may register our input late
+)
+
+// inputTracker wraps a Platform and remembers when we last moved or clicked.
+type inputTracker struct {
+	Platform
+	last atomic.Int64 // UnixNano of our last synthetic input
+}
+
+func (t *inputTracker) SetCursorPos(x, y int) {
+	t.Platform.SetCursorPos(x, y)
+	t.last.Store(time.Now().UnixNano())
+}
+
+func (t *inputTracker) MouseDown(b mouseButton, count int) {
+	t.Platform.MouseDown(b, count)
+	t.last.Store(time.Now().UnixNano())
+}
+
+func (t *inputTracker) MouseUp(b mouseButton, count int) {
+	t.Platform.MouseUp(b, count)
+	t.last.Store(time.Now().UnixNano())
+}
+
+func (t *inputTracker) Scroll(ticks int) {
+	t.Platform.Scroll(ticks)
+	t.last.Store(time.Now().UnixNano())
+}
+
+func (t *inputTracker) TapKey(k key) {
+	t.Platform.TapKey(k)
+	t.last.Store(time.Now().UnixNano())
+}
+
+// userActive reports whether the user produced input within threshold.
+func (t *inputTracker) userActive(threshold time.Duration) bool {
+	idle := t.IdleTime()
+	if idle >= threshold {
+		return false
+	}
+	lastInput := time.Now().Add(-idle)
+	ours := time.Unix(0, t.last.Load()).Add(syntheticSlack)
+	return lastInput.After(ours)
+}
+
+func (e *engine) idleThreshold() time.Duration {
+	return ms(e.cfg.Timing.IdleMs)
+}
+
+func (e *engine) userActive() bool {
+	return e.idleThreshold() > 0 && e.p.userActive(e.idleThreshold())
+}
+
+// waitForIdle blocks until the user has been idle for the threshold. It
+// reports false if ctx is done first.
+func (e *engine) waitForIdle(ctx context.Context) bool {
+	for e.userActive() {
+		if !sleepWithCancel(ctx, idlePoll) {
+			return false
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// yieldOnInput returns a child of ctx that is cancelled as soon as the user
+// touches input, so a cycle in progress stops immediately. The returned stop
+// cancels it and waits for the input watcher to exit.
+func (e *engine) yieldOnInput(ctx context.Context) (context.Context, func()) {
+	cctx, cancel := context.WithCancel(ctx)
+	if e.idleThreshold() <= 0 {
+		return cctx, cancel
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(idlePoll)
+		defer t.Stop()
+		for {
+			select {
+			case <-cctx.Done():
+				return
+			case <-t.C:
+				if e.userActive() {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return cctx, func() {
+		cancel()
+		<-done
+	}
+}
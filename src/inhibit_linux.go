@@ -0,0 +1,89 @@
+//go:build linux
+
+package main
+
+/*
+#cgo pkg-config: dbus-1
+#include <stdlib.h>
+#include "dbus_linux.h"
+*/
+import "C"
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// ── D-Bus sleep inhibition ──────────────────────────────────────────────────
+// dbusInhibitor holds a systemd-logind idle/sleep lock and an
+// org.freedesktop.ScreenSaver inhibition. Either one is enough; Prevent
+// reports false only if neither service answered, so the caller can fall
+// back to the X11 screensaver extension.
+
+const (
+	inhibitWho    = "Clicky"
+	inhibitReason = "KeepAlive active"
+)
+
+type dbusInhibitor struct {
+	mu        sync.Mutex
+	fd        int    // logind inhibitor lock, -1 if not held
+	cookie    uint32 // ScreenSaver cookie, valid if hasCookie
+	hasCookie bool
+}
+
+func newDBusInhibitor() *dbusInhibitor {
+	return &dbusInhibitor{fd: -1}
+}
+
+func cString(s string) (*C.char, func()) {
+	cs := C.CString(s)
+	return cs, func() { C.free(unsafe.Pointer(cs)) }
+}
+
+// Prevent takes whichever locks are not yet held and reports whether at
+// least one is now in place.
+func (d *dbusInhibitor) Prevent() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.fd < 0 {
+		what, freeWhat := cString("idle:sleep")
+		who, freeWho := cString(inhibitWho)
+		why, freeWhy := cString(inhibitReason)
+		d.fd = int(C.dbusLogindInhibit(what, who, why))
+		freeWhat()
+		freeWho()
+		freeWhy()
+	}
+
+	if !d.hasCookie {
+		app, freeApp := cString(inhibitWho)
+		reason, freeReason := cString(inhibitReason)
+		var cookie C.uint
+		if C.dbusScreenSaverInhibit(app, reason, &cookie) != 0 {
+			d.cookie = uint32(cookie)
+			d.hasCookie = true
+		}
+		freeApp()
+		freeReason()
+	}
+
+	return d.fd >= 0 || d.hasCookie
+}
+
+// Allow releases every lock taken by Prevent.
+func (d *dbusInhibitor) Allow() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.fd >= 0 {
+		syscall.Close(d.fd)
+		d.fd = -1
+	}
+	if d.hasCookie {
+		C.dbusScreenSaverUnInhibit(C.uint(d.cookie))
+		d.hasCookie = false
+	}
+}
@@ -0,0 +1,114 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestDBusInhibitor runs a private bus with no logind on it, first with no
+// ScreenSaver service and then with the stand-in from testdata/screensaver.
+// Both bus addresses point at it. libdbus reads them only once per process,
+// so the address is the same on every run (-count=2 restarts the bus under
+// the shared connection).
+func TestDBusInhibitor(t *testing.T) {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	addr := fmt.Sprintf("unix:abstract=clicky-test-%d", os.Getpid())
+	bus := exec.Command(daemon, "--session", "--nofork", "--print-address", "--address="+addr)
+	out, _ := bus.StdoutPipe()
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		bus.Process.Kill()
+		bus.Wait()
+	}()
+	// The daemon prints its address once it listens
+	if _, err := bufio.NewReader(out).ReadString('\n'); err != nil {
+		t.Fatalf("starting dbus-daemon: %v", err)
+	}
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", addr)
+	t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", addr)
+
+	d := newDBusInhibitor()
+	if d.Prevent() {
+		t.Fatal("Prevent reported a lock with no service on the bus")
+	}
+
+	calls := startScreenSaver(t)
+	if !d.Prevent() {
+		t.Fatal("Prevent got no lock from the ScreenSaver service")
+	}
+	if want := fmt.Sprintf("inhibit %d", d.cookie); !d.hasCookie || calls.next(t) != want {
+		t.Fatalf("after Prevent: hasCookie %v, cookie %d; want the service's %q", d.hasCookie, d.cookie, want)
+	}
+	cookie := d.cookie
+	d.Allow()
+	if got, want := calls.next(t), fmt.Sprintf("uninhibit %d", cookie); got != want {
+		t.Errorf("Allow: service saw %q, want %q", got, want)
+	}
+	if d.hasCookie {
+		t.Error("cookie still held after Allow")
+	}
+}
+
+// serviceCalls is the stand-in's stdout, one call per line.
+type serviceCalls chan string
+
+func (c serviceCalls) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case line := <-c:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("no call reached the ScreenSaver service")
+		return ""
+	}
+}
+
+// startScreenSaver builds and starts the stand-in service and waits until
+// it owns its name.
+func startScreenSaver(t *testing.T) serviceCalls {
+	t.Helper()
+	gocmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found to build the stand-in service")
+	}
+	bin := filepath.Join(t.TempDir(), "screensaver")
+	build := exec.Command(gocmd, "build", "-o", bin, "./testdata/screensaver")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building the stand-in service: %v\n%s", err, out)
+	}
+
+	svc := exec.Command(bin)
+	svc.Stderr = os.Stderr
+	out, _ := svc.StdoutPipe()
+	if err := svc.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		svc.Process.Kill()
+		svc.Wait()
+	})
+
+	calls := make(serviceCalls, 8)
+	go func() {
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			calls <- sc.Text()
+		}
+	}()
+	if line := calls.next(t); line != "ready" {
+		t.Fatalf("stand-in service said %q, want \"ready\"", line)
+	}
+	return calls
+}
@@ -1,9 +1,173 @@
 package main
 
-import "runtime"
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+type options struct {
+	configPath string
+	start      bool          // begin active immediately
+	duration   time.Duration // quit after this long (0 = never)
+	interval   time.Duration // fixed pause between cycles (0 = config)
+	noClick    bool
+	noWindow   bool
+	forD       time.Duration // stop after running this long (0 = config)
+	until      string        // stop at this time of day (config if empty)
+	seed       int64         // random seed (0 = config)
+	record     bool          // save the user's own mouse strokes as templates
+}
+
+func parseFlags() options {
+	var o options
+	fs := flag.NewFlagSet("clicky", flag.ExitOnError)
+	fs.StringVar(&o.configPath, "config", defaultConfigPath(), "config file `path`")
+	fs.BoolVar(&o.start, "start", false, "start active immediately")
+	fs.DurationVar(&o.duration, "duration", 0, "quit after this long, e.g. 2h30m")
+	fs.DurationVar(&o.interval, "interval", 0, "fixed pause between cycles instead of the random delay, e.g. 3s")
+	fs.BoolVar(&o.noClick, "no-click", false, "move the cursor but never click")
+	fs.BoolVar(&o.noWindow, "no-window", false, "run headless: no window, starts immediately, never clicks")
+	fs.DurationVar(&o.forD, "for", 0, "stop (but keep running) this long after starting, e.g. 2h")
+	fs.StringVar(&o.until, "until", "", "stop (but keep running) at this time of day, `HH:MM`")
+	fs.Int64Var(&o.seed, "seed", 0, "random seed, to replay a session's delays and curves")
+	fs.BoolVar(&o.record, "record", false, "save your own mouse movements as templates for the template path")
+
+	// Finder on older macOS passes -psn_<id>; it is not ours to parse
+	var args []string
+	for _, a := range os.Args[1:] {
+		if !strings.HasPrefix(a, "-psn_") {
+			args = append(args, a)
+		}
+	}
+	fs.Parse(args)
+	return o
+}
+
+// apply folds the flags into cfg.
+func (o options) apply(cfg *config) error {
+	if o.interval < 0 || o.duration < 0 || o.forD < 0 {
+		return fmt.Errorf("--interval, --duration and --for must not be negative")
+	}
+	switch {
+	case o.forD > 0 && o.until != "":
+		return fmt.Errorf("use --for or --until, not both")
+	case o.forD > 0:
+		if o.forD < time.Minute {
+			return fmt.Errorf("--for must be at least a minute")
+		}
+		cfg.Timer.ForMin = int(o.forD.Round(time.Minute) / time.Minute)
+		cfg.Timer.Until = ""
+	case o.until != "":
+		cfg.Timer.ForMin = 0
+		cfg.Timer.Until = o.until
+	}
+	if o.seed != 0 {
+		cfg.Seed = o.seed
+	}
+	if o.interval > 0 {
+		cfg.Timing.DelayMinMs = int(o.interval / time.Millisecond)
+		cfg.Timing.DelayMaxMs = cfg.Timing.DelayMinMs
+		cfg.Timing.DelayDist = distFixed
+		cfg.Timing.DelayMeanMs = 0
+	}
+	if (o.noClick || o.noWindow) && cfg.Mode == modeClick {
+		cfg.Mode = modeMove
+	}
+	return cfg.validate()
+}
 
 func main() {
 	runtime.LockOSThread()
-	initApp()
-	platformRun()
+
+	opts := parseFlags()
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clicky: config:", err, "(using defaults)")
+	}
+	if err := opts.apply(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "clicky:", err)
+		os.Exit(2)
+	}
+
+	var p Platform = newPlatform(cfg.Window)
+	if opts.noWindow {
+		p = newHeadlessPlatform(p, cfg.Window)
+	}
+	e := newEngine(p, cfg)
+	initApp(e)
+	fmt.Fprintf(os.Stderr, "clicky: random seed %d\n", e.Seed())
+
+	// Helpers that start when the window is ready and stop before we quit.
+	// The schedule goes first so --start can override a closed window.
+	cd := newCountdown(e, cfg.Timer, systemClock{})
+	onTimerClicked = cd.Cycle
+	ready := []func(){cd.Start}
+	stops := []func(){cd.Stop}
+	if cfg.Schedule.enabled() {
+		sched, _ := cfg.Schedule.compile() // validated with the config
+		sc := newScheduler(e, sched, systemClock{})
+		ready = append(ready, sc.Start)
+		stops = append(stops, sc.Stop)
+	}
+	var rec *recorder
+	if opts.record {
+		rec = newRecorder(e, cfg.Curve.templatesPath())
+		ready = append(ready, rec.Start)
+		stops = append(stops, rec.Stop)
+		fmt.Fprintf(os.Stderr, "clicky: recording movement templates to %s\n", cfg.Curve.templatesPath())
+	}
+	if opts.start || opts.noWindow {
+		ready = append(ready, e.Start)
+	}
+	onWindowReady = func() {
+		for _, f := range ready {
+			f()
+		}
+	}
+	stopHelpers := func() {
+		for _, f := range stops {
+			f()
+		}
+	}
+	quit := onHotkeyQuit
+	onHotkeyQuit = func() {
+		stopHelpers()
+		quit()
+	}
+	if opts.duration > 0 {
+		time.AfterFunc(opts.duration, onHotkeyQuit)
+	}
+	if opts.noWindow {
+		// Without a window nothing could start the engine again
+		var once sync.Once
+		e.onFailsafe = func() {
+			once.Do(func() {
+				fmt.Fprintln(os.Stderr, "clicky: fail-safe corner hit; quitting")
+				onHotkeyQuit()
+			})
+		}
+	}
+
+	p.Run()
+
+	// The window is gone; make sure the loop is too
+	stopHelpers()
+	e.Stop()
+	e.Wait()
+
+	if n := e.SkippedClicks(); n > 0 {
+		fmt.Fprintf(os.Stderr, "clicky: skipped %d clicks that would have missed the button\n", n)
+	}
+	if rec != nil {
+		n, err := rec.Result()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clicky: recording:", err)
+		}
+		fmt.Fprintf(os.Stderr, "clicky: recorded %d movement templates\n", n)
+	}
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOptionsApply checks that flags win over the config file and that
+// the config keeps whatever no flag sets.
+func TestOptionsApply(t *testing.T) {
+	// fromFile is a config as loaded from a file that sets everything the
+	// flags can override
+	fromFile := func() *config {
+		c := defaultConfig()
+		c.Timer.Until = "17:45"
+		c.Seed = 42
+		c.Mode = modeClick
+		c.Timing.DelayDist, c.Timing.DelayMeanMs = distNormal, 2500
+		return c
+	}
+	for _, tc := range []struct {
+		name  string
+		opts  options
+		check func(c *config) bool
+	}{
+		{"no flags", options{}, func(c *config) bool {
+			return c.Timer.Until == "17:45" && c.Seed == 42 && c.Mode == modeClick && c.Timing.DelayDist == distNormal
+		}},
+		{"--for over until", options{forD: 90 * time.Minute}, func(c *config) bool {
+			return c.Timer.ForMin == 90 && c.Timer.Until == ""
+		}},
+		{"--for rounds to minutes", options{forD: 90*time.Minute + 40*time.Second}, func(c *config) bool {
+			return c.Timer.ForMin == 91
+		}},
+		{"--until over until", options{until: "08:15"}, func(c *config) bool {
+			return c.Timer.Until == "08:15" && c.Timer.ForMin == 0
+		}},
+		{"--seed", options{seed: 7}, func(c *config) bool { return c.Seed == 7 }},
+		{"--interval", options{interval: 3 * time.Second}, func(c *config) bool {
+			t := c.Timing
+			return t.DelayMinMs == 3000 && t.DelayMaxMs == 3000 && t.DelayDist == distFixed && t.DelayMeanMs == 0
+		}},
+		{"--no-click", options{noClick: true}, func(c *config) bool { return c.Mode == modeMove }},
+		{"--no-window", options{noWindow: true}, func(c *config) bool { return c.Mode == modeMove }},
+	} {
+		c := fromFile()
+		if err := tc.opts.apply(c); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if !tc.check(c) {
+			t.Errorf("%s: config %+v", tc.name, *c)
+		}
+	}
+
+	// Without a window nothing could click, but key and sleep modes stay
+	for _, m := range []mode{modeKey, modeSleep} {
+		c := fromFile()
+		c.Mode = m
+		if err := (options{noClick: true, noWindow: true}).apply(c); err != nil || c.Mode != m {
+			t.Errorf("%s with --no-click --no-window: mode %s (%v)", m, c.Mode, err)
+		}
+	}
+
+	// --until over a config for_min
+	c := defaultConfig()
+	c.Timer.ForMin = 30
+	if err := (options{until: "18:00"}).apply(c); err != nil || c.Timer.ForMin != 0 || c.Timer.Until != "18:00" {
+		t.Errorf("--until over for_min: %+v (%v)", c.Timer, err)
+	}
+}
+
+func TestOptionsApplyRejects(t *testing.T) {
+	for _, tc := range []struct {
+		opts options
+		want string
+	}{
+		{options{interval: -time.Second}, "--interval, --duration and --for must not be negative"},
+		{options{duration: -time.Second}, "--interval, --duration and --for must not be negative"},
+		{options{forD: -time.Minute}, "--interval, --duration and --for must not be negative"},
+		{options{forD: time.Hour, until: "17:00"}, "use --for or --until, not both"},
+		{options{forD: 30 * time.Second}, "--for must be at least a minute"},
+		{options{until: "25:00"}, "timer: until:"},
+		{options{interval: 2 * time.Hour}, "timing: delay_max_ms 7200000 is over an hour"},
+	} {
+		err := tc.opts.apply(defaultConfig())
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("%+v: error %v, want %q…", tc.opts, err, tc.want)
+		}
+	}
+}
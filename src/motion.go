@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ── Motion profile ──────────────────────────────────────────────────────────
+// How fast the cursor travels along a path. With an easing other than none,
+// a glide takes a Fitts'-law time, a + b·log2(D/W + 1) for distance D and
+// button width W, and the path is walked by arc length, so the speed follows
+// the easing curve instead of however the path happens to be parameterised.
+
+const (
+	easeNone        = "none"         // cc.Steps equal steps in t, whatever the distance
+	easeLinear      = "linear"       // constant speed
+	easeInOut       = "ease-in-out"  // cubic: speed up, then slow down
+	easeMinimumJerk = "minimum-jerk" // smoothest start and stop, like a reaching hand
+)
+
+var easings = []string{easeNone, easeLinear, easeInOut, easeMinimumJerk}
+
+// arcSamples is how finely a path is measured for arc-length resampling.
+const arcSamples = 256
+
+// easing maps time fraction to distance fraction; both run 0 → 1.
+type easing func(s float64) float64
+
+func newEasing(name string) (easing, error) {
+	switch name {
+	case easeNone, "":
+		return nil, nil
+	case easeLinear:
+		return func(s float64) float64 { return s }, nil
+	case easeInOut:
+		return func(s float64) float64 {
+			if s < 0.5 {
+				return 4 * s * s * s
+			}
+			u := 2*s - 2
+			return 1 + u*u*u/2
+		}, nil
+	case easeMinimumJerk:
+		return func(s float64) float64 {
+			// 10s³ − 15s⁴ + 6s⁵
+			return s * s * s * (10 + s*(-15+6*s))
+		}, nil
+	}
+	return nil, fmt.Errorf("curve: easing must be one of %v, got %q", easings, name)
+}
+
+// byArcLength reparameterises c so that c'(u) lies at fraction u of the
+// path's length. Lengths are measured on arcSamples chords.
+func (c path) byArcLength() path {
+	ts := make([]float64, arcSamples+1)
+	ds := make([]float64, arcSamples+1)
+	prev := c(0)
+	for i := 1; i <= arcSamples; i++ {
+		ts[i] = float64(i) / arcSamples
+		p := c(ts[i])
+		ds[i] = ds[i-1] + prev.dist(p)
+		prev = p
+	}
+	total := ds[arcSamples]
+	if total == 0 {
+		return c
+	}
+	return func(u float64) point {
+		if u >= 1 {
+			return c(1)
+		}
+		want := u * total
+		i := sort.SearchFloat64s(ds, want)
+		if i == 0 {
+			return c(0)
+		}
+		// Interpolate t within the chord that covers want
+		f := (want - ds[i-1]) / (ds[i] - ds[i-1])
+		return c(ts[i-1] + f*(ts[i]-ts[i-1]))
+	}
+}
+
+// glide is everything about how the cursor travels to a target: the path
+// shape, the speed profile and the timing.
+type glide struct {
+	gen     pathGen
+	ease    easing // nil: cc.Steps equal steps in t
+	cc      curveConfig
+	targetW float64 // button width, W in Fitts' law
+}
+
+func newGlide(cc curveConfig, targetW int) (glide, error) {
+	gen, err := newPathGen(cc)
+	if err != nil {
+		return glide{}, err
+	}
+	ease, err := newEasing(cc.Easing)
+	if err != nil {
+		return glide{}, err
+	}
+	return glide{gen: gen, ease: ease, cc: cc, targetW: float64(targetW)}, nil
+}
+
+// duration is the Fitts'-law movement time for a glide of dist px.
+func (g glide) duration(dist float64) time.Duration {
+	bits := math.Log2(dist/g.targetW + 1)
+	return ms(g.cc.FittsAMs) + time.Duration(bits*float64(g.cc.FittsBMs)*float64(time.Millisecond))
+}
+
+// plan returns the path for a glide from → to on a screen of the given size
+// and how to walk it: the number of steps and the pause after each. With
+// overshoot the glide lands past the target and a short second glide brings
+// it back; tremor wobbles the path but not its ends. Either way the path
+// still ends exactly at to.
+func (g glide) plan(from, to, screen point, r randSource) (path, int, time.Duration) {
+	var curve path
+	var steps int
+	var step time.Duration
+	if over, ok := g.overshoot(from, to, screen, r); ok {
+		main, n1, s1 := g.segment(g.gen, from, over, screen, r)
+		fix, n2, s2 := g.segment(quadraticPath{}, over, to, screen, r)
+		if s1 > 0 && s2 != s1 {
+			// With step_ms 0 each glide has its own step; walk the
+			// correction at the main glide's, in as many steps as its time needs
+			n2 = max(1, int(math.Round(float64(s2)*float64(n2)/float64(s1))))
+		}
+		curve, steps, step = joinPaths(main, n1, fix, n2), n1+n2, s1
+	} else {
+		curve, steps, step = g.segment(g.gen, from, to, screen, r)
+	}
+
+	if g.cc.TremorChance > 0 && r.Float64() < g.cc.TremorChance {
+		curve = withTremor(curve, float64(g.cc.TremorPx), r)
+	}
+	return curve, steps, step
+}
+
+// segment plans one glide along a path from gen.
+func (g glide) segment(gen pathGen, from, to, screen point, r randSource) (path, int, time.Duration) {
+	curve := gen.Path(from, to, screen, r)
+	step := ms(g.cc.StepMs)
+	if g.ease == nil {
+		return curve, g.cc.Steps, step
+	}
+
+	// Fitts' time, split into step_ms steps (or into steps when step_ms is 0)
+	d := g.duration(from.dist(to))
+	steps := g.cc.Steps
+	if step > 0 {
+		steps = max(2, int(math.Ceil(float64(d)/float64(step))))
+	} else if steps > 0 {
+		step = d / time.Duration(steps)
+	}
+
+	even := curve.byArcLength()
+	ease := g.ease
+	return func(s float64) point { return even(ease(s)) }, steps, step
+}
+
+// overshootEdge keeps overshoot points this far inside the screen, so the
+// OS never clamps the cursor (which would read as the user moving it) and
+// the fail-safe corner stays out of reach.
+const overshootEdge = 10
+
+// overshoot decides whether this glide overshoots and, if so, where it
+// lands: up to overshoot_px past the target and a little to the side.
+func (g glide) overshoot(from, to, screen point, r randSource) (point, bool) {
+	if g.cc.OvershootChance <= 0 || r.Float64() >= g.cc.OvershootChance {
+		return point{}, false
+	}
+	d := from.dist(to)
+	if d < 2*float64(g.cc.OvershootPx) {
+		return point{}, false // short hops land directly
+	}
+	amt := float64(g.cc.OvershootPx) * (0.5 + 0.5*r.Float64())
+	side := (r.Float64()*2 - 1) * amt / 3
+
+	dir := to.sub(from).scale(1 / d)
+	perp := point{-dir.Y, dir.X}
+	over := to.add(dir.scale(amt)).add(perp.scale(side))
+	if over.X < overshootEdge || over.Y < overshootEdge ||
+		over.X > screen.X-1-overshootEdge || over.Y > screen.Y-1-overshootEdge {
+		return point{}, false
+	}
+	return over, true
+}
+
+// joinPaths runs a over the first na of na+nb equal steps and b over the rest.
+func joinPaths(a path, na int, b path, nb int) path {
+	n := float64(na + nb)
+	return func(s float64) point {
+		i := s * n
+		if i <= float64(na) {
+			return a(i / float64(na))
+		}
+		return b((i - float64(na)) / float64(nb))
+	}
+}
+
+// withTremor adds a smoothed random wobble of about amp px, faded in and out
+// by sin(πs) so the path starts and ends where it did. It draws from r on
+// every call, so it suits a path walked once, in order.
+func withTremor(c path, amp float64, r randSource) path {
+	var off point
+	return func(s float64) point {
+		if s <= 0 || s >= 1 {
+			return c(s)
+		}
+		// AR(1) noise: each step keeps most of the last offset, so the
+		// wobble is a slow drift of about amp px rather than per-step jitter
+		off = off.scale(0.8).add(point{r.NormFloat64(), r.NormFloat64()}.scale(amp * 0.6))
+		return c(s).add(off.scale(math.Sin(math.Pi * s)))
+	}
+}
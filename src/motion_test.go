@@ -0,0 +1,174 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// maxSlope is the steepest each easing gets: its peak speed relative to
+// constant speed.
+var maxSlope = map[string]float64{
+	easeLinear:      1,
+	easeInOut:       3,     // 12s² at s = 0.5
+	easeMinimumJerk: 1.875, // 30s²(1-s)² at s = 0.5
+}
+
+// arcTable samples c densely and returns the points with the path length
+// up to each.
+func arcTable(c path) ([]point, []float64) {
+	const n = 4096
+	pts := make([]point, n+1)
+	ls := make([]float64, n+1)
+	pts[0] = c(0)
+	for i := 1; i <= n; i++ {
+		pts[i] = c(float64(i) / n)
+		ls[i] = ls[i-1] + pts[i-1].dist(pts[i])
+	}
+	return pts, ls
+}
+
+// progress is how far along the sampled path q lies: the length up to the
+// nearest sample.
+func progress(q point, pts []point, ls []float64) float64 {
+	best := 0
+	for i := range pts {
+		if q.dist(pts[i]) < q.dist(pts[best]) {
+			best = i
+		}
+	}
+	return ls[best]
+}
+
+func TestByArcLength(t *testing.T) {
+	from, to, screen := point{100, 900}, point{1700, 150}, point{1920, 1080}
+	for _, gen := range []pathGen{straightPath{}, quadraticPath{}, cubicPath{}, catmullRomPath{3}} {
+		c := gen.Path(from, to, screen, rand.New(rand.NewSource(3)))
+		_, ls := arcTable(c)
+		total := ls[len(ls)-1]
+
+		even := c.byArcLength()
+		const n = 100
+		for i := 1; i <= n; i++ {
+			u0, u1 := float64(i-1)/n, float64(i)/n
+			if d := even(u0).dist(even(u1)); math.Abs(d-total/n) > 0.02*total/n {
+				t.Errorf("%T: step %d covers %.2f px, want %.2f", gen, i, d, total/n)
+			}
+		}
+		if even(0) != from || even(1) != to {
+			t.Errorf("%T: runs %v → %v, want %v → %v", gen, even(0), even(1), from, to)
+		}
+	}
+}
+
+// TestPlanSpeed walks planned glides step by step. Progress along the path
+// must never go back, and with an easing no step may be longer than the
+// easing's peak speed allows over one step of the Fitts'-law duration.
+func TestPlanSpeed(t *testing.T) {
+	from, to, screen := point{100, 900}, point{1700, 150}, point{1920, 1080}
+	for _, easing := range []string{easeNone, easeLinear, easeInOut, easeMinimumJerk} {
+		for _, name := range []string{pathStraight, pathQuadratic, pathCubic, pathCatmullRom} {
+			for _, stepMs := range []int{4, 0} {
+				cc := defaultConfig().Curve
+				cc.Easing, cc.Path, cc.StepMs = easing, name, stepMs
+				cc.OvershootChance, cc.TremorChance = 0, 0
+				g, err := newGlide(cc, 80)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Without overshoot and tremor, plan draws the same path as gen
+				curve, steps, step := g.plan(from, to, screen, rand.New(rand.NewSource(4)))
+				pts, ls := arcTable(g.gen.Path(from, to, screen, rand.New(rand.NewSource(4))))
+				total, slack := ls[len(ls)-1], 2*ls[len(ls)-1]/float64(len(ls)-1)
+
+				var bound float64
+				if easing != easeNone {
+					d := g.duration(from.dist(to))
+					bound = total*maxSlope[easing]*float64(step)/float64(d)*1.02 + 0.5
+				}
+				prev, last := 0.0, from
+				for i := 1; i <= steps; i++ {
+					q := curve(float64(i) / float64(steps))
+					if p := progress(q, pts, ls); p < prev-slack {
+						t.Fatalf("%s %s step_ms %d: step %d goes back from %.1f to %.1f px", easing, name, stepMs, i, prev, p)
+					} else {
+						prev = max(prev, p)
+					}
+					if d := q.dist(last); bound > 0 && d > bound {
+						t.Errorf("%s %s step_ms %d: step %d/%d covers %.1f px, over %.1f", easing, name, stepMs, i, steps, d, bound)
+					}
+					last = q
+				}
+				if step <= 0 && easing != easeNone {
+					t.Errorf("%s %s step_ms %d: no pause between steps", easing, name, stepMs)
+				}
+				if want := time.Duration(stepMs) * time.Millisecond; stepMs > 0 && step != want {
+					t.Errorf("%s %s: step %v, want step_ms %v", easing, name, step, want)
+				}
+			}
+		}
+	}
+}
+
+// TestPlanEndsOnTarget makes every glide overshoot and tremble, which must
+// still leave the cursor exactly on the target.
+func TestPlanEndsOnTarget(t *testing.T) {
+	screen := point{1920, 1080}
+	r := rand.New(rand.NewSource(5))
+	for _, easing := range easings {
+		for _, name := range pathGens {
+			cc := defaultConfig().Curve
+			cc.Easing, cc.Path = easing, name
+			cc.OvershootChance, cc.TremorChance = 1, 1
+			if name == pathTemplate {
+				cc.Path = pathStraight // no library on disk; swapped in below
+			}
+			g, err := newGlide(cc, 80)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name == pathTemplate {
+				g.gen = templatePath{testTemplates}
+			}
+			for i := 0; i < 50; i++ {
+				from := point{math.Round(r.Float64() * 1919), math.Round(r.Float64() * 1079)}
+				to := point{math.Round(r.Float64() * 1919), math.Round(r.Float64() * 1079)}
+				curve, steps, _ := g.plan(from, to, screen, r)
+				if steps < 1 {
+					t.Fatalf("%s %s: %d steps", easing, name, steps)
+				}
+				// Walk it in order: the tremor draws as it goes
+				for j := 0; j < steps; j++ {
+					curve(float64(j) / float64(steps))
+				}
+				if got := curve(1); got != to {
+					t.Fatalf("%s %s: %v → %v ends at %v", easing, name, from, to, got)
+				}
+			}
+		}
+	}
+}
+
+// TestOvershootTiming checks that the correction after an overshoot takes
+// its own Fitts'-law time whether step_ms sets the step or the step count
+// does.
+func TestOvershootTiming(t *testing.T) {
+	from, to, screen := point{100, 900}, point{1700, 150}, point{1920, 1080}
+	total := func(stepMs int) (time.Duration, time.Duration) {
+		cc := defaultConfig().Curve
+		cc.StepMs, cc.OvershootChance, cc.OvershootPx = stepMs, 1, 200
+		g, err := newGlide(cc, 80)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, steps, step := g.plan(from, to, screen, rand.New(rand.NewSource(6)))
+		return time.Duration(steps) * step, step
+	}
+	byStepMs, _ := total(4)
+	bySteps, step := total(0)
+	if diff := bySteps - byStepMs; diff < -step-8*time.Millisecond || diff > step+8*time.Millisecond {
+		t.Errorf("glide takes %v with step_ms 0 but %v with step_ms 4", bySteps, byStepMs)
+	}
+}
@@ -2,15 +2,25 @@
 #define OBJC_DARWIN_H
 
 void setIconData(const void *data, int length);
+void macSetLayout(int winW, int winH, int btnW, int btnH, int modeX, int modeY, int modeW, int modeH,
+                  int timerX, int timerY, int timerW, int timerH, int statusY);
 void createAndRunGUI(void);
 void macSetCursorPos(int x, int y);
 void macGetCursorPos(int *outX, int *outY);
-void macClick(void);
+void macScreenSize(int *outW, int *outH);
+void macMouseButton(int button, int down, int clickCount);
+void macScroll(int ticks);
+void macTapKey(int code, int isModifier);
+int macCursorOverButton(void);
+double macIdleSeconds(void);
 void macPreventSleep(void);
 void macAllowSleep(void);
 void macMoveButton(int x, int y);
 void macClientToScreen(int cx, int cy, int *outX, int *outY);
 void macSetButtonActive(int isActive);
+void macSetModeLabel(const char *text);
+void macSetStatus(const char *text);
+void macSetTimerLabel(const char *text);
 void macReinforceTopmost(void);
 void macQuit(void);
 
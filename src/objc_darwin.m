@@ -7,22 +7,51 @@
 
 static NSWindow     *mainWindow   = nil;
 static NSButton     *aliveButton  = nil;
+static NSButton     *modeButton   = nil;
+static NSString     *modeText     = @"";
+static NSButton     *timerButton  = nil;
+static NSString     *timerText    = @"";
+static NSTextField  *statusLabel  = nil;
+static NSString     *statusText   = @"";
 static IOPMAssertionID sleepAssertionID = 0;
+static BOOL         terminateReplyPending = NO;
+
+// Window geometry (client area + button), set from Go before the GUI starts
+static CGFloat layoutWinW = 300, layoutWinH = 300;
+static CGFloat layoutBtnW = 80,  layoutBtnH = 30;
+static NSRect  layoutMode = {{100, 10}, {100, 30}}; // top-left origin
+static NSRect  layoutTimer = {{100, 175}, {100, 30}}; // top-left origin
+static CGFloat layoutStatusY = 95;                   // top-left origin
 
 // Forward declarations for Go callbacks
 extern void goOnButtonClicked();
 extern void goOnHotkeyQuit();
+extern void goOnModeClicked();
+extern void goOnTimerClicked();
+extern void goOnWindowReady();
 
 // ── Button action target ────────────────────────────────────────────────────
 
 @interface ButtonTarget : NSObject
 - (void)buttonClicked:(id)sender;
+- (void)modeClicked:(id)sender;
+- (void)timerClicked:(id)sender;
+- (void)quitClicked:(id)sender;
 @end
 
 @implementation ButtonTarget
 - (void)buttonClicked:(id)sender {
     goOnButtonClicked();
 }
+- (void)modeClicked:(id)sender {
+    goOnModeClicked();
+}
+- (void)timerClicked:(id)sender {
+    goOnTimerClicked();
+}
+- (void)quitClicked:(id)sender {
+    goOnHotkeyQuit();
+}
 @end
 
 static ButtonTarget *btnTarget = nil;
@@ -34,22 +63,26 @@ static ButtonTarget *btnTarget = nil;
 
 @implementation WindowDelegate
 - (BOOL)windowShouldClose:(NSWindow *)sender {
-    [NSApp terminate:nil];
+    goOnHotkeyQuit();
     return NO;
 }
 @end
 
 static WindowDelegate *winDel = nil;
 
-// ── App delegate (Cmd+Q) ───────────────────────────────────────────────────
+// ── App delegate (logout, restart, Dock quit) ──────────────────────────────
 
 @interface AppDelegate : NSObject <NSApplicationDelegate>
 @end
 
 @implementation AppDelegate
+// The system asks; Go stops the engine and then calls macQuit, which
+// answers. Cmd+Q and the close button skip this and go to Go directly, so
+// [NSApp run] ends and main can report before the process exits.
 - (NSApplicationTerminateReply)applicationShouldTerminate:(NSApplication *)sender {
+    terminateReplyPending = YES;
     goOnHotkeyQuit();
-    return NSTerminateNow;
+    return NSTerminateLater;
 }
 @end
 
@@ -65,6 +98,53 @@ void setIconData(const void *data, int length) {
     iconPNGLength = length;
 }
 
+void macSetLayout(int winW, int winH, int btnW, int btnH, int modeX, int modeY, int modeW, int modeH,
+                  int timerX, int timerY, int timerW, int timerH, int statusY) {
+    layoutWinW = winW;
+    layoutWinH = winH;
+    layoutBtnW = btnW;
+    layoutBtnH = btnH;
+    layoutMode = NSMakeRect(modeX, modeY, modeW, modeH);
+    layoutTimer = NSMakeRect(timerX, timerY, timerW, timerH);
+    layoutStatusY = statusY;
+}
+
+// setSelectorTitle gives a mode or timer selector its dim, small title.
+static void setSelectorTitle(NSButton *button, NSString *text) {
+    NSMutableAttributedString *attrTitle = [[NSMutableAttributedString alloc] initWithString:text];
+    [attrTitle addAttribute:NSForegroundColorAttributeName value:[NSColor colorWithWhite:0.78 alpha:1.0] range:NSMakeRange(0, attrTitle.length)];
+    [attrTitle addAttribute:NSFontAttributeName value:[NSFont systemFontOfSize:12] range:NSMakeRange(0, attrTitle.length)];
+    [button setAttributedTitle:attrTitle];
+    [attrTitle release];
+}
+
+static void applyModeTitle(void) {
+    setSelectorTitle(modeButton, modeText);
+}
+
+// newSelector makes a flat selector button; rect has a top-left origin.
+static NSButton *newSelector(NSRect rect, SEL action) {
+    NSRect frame = NSMakeRect(rect.origin.x, layoutWinH - rect.origin.y - rect.size.height,
+                              rect.size.width, rect.size.height);
+    NSButton *button = [[NSButton alloc] initWithFrame:frame];
+    [button setBordered:NO];
+    [button setWantsLayer:YES];
+    [button.layer setBackgroundColor:[[NSColor colorWithRed:0x3C/255.0 green:0x3C/255.0 blue:0x3C/255.0 alpha:1.0] CGColor]];
+    [button.layer setCornerRadius:4];
+    [button setTarget:btnTarget];
+    [button setAction:action];
+    return button;
+}
+
+// applyStatus shows statusText centred on the status row.
+static void applyStatus(void) {
+    [statusLabel setStringValue:statusText];
+    [statusLabel sizeToFit];
+    NSSize sz = statusLabel.frame.size;
+    CGFloat midY = layoutWinH - layoutStatusY - layoutBtnH / 2;
+    [statusLabel setFrameOrigin:NSMakePoint((layoutWinW - sz.width) / 2, midY - sz.height / 2)];
+}
+
 static void macSetAppIconFromPNG(const void *data, int length) {
     NSData *pngData = [NSData dataWithBytes:data length:(NSUInteger)length];
     NSImage *icon = [[NSImage alloc] initWithData:pngData];
@@ -84,7 +164,7 @@ void createAndRunGUI() {
         // Screen geometry (macOS: origin = bottom-left)
         NSScreen *screen = [NSScreen mainScreen];
         CGFloat screenH = screen.frame.size.height;
-        CGFloat winW = 300, winH = 300;
+        CGFloat winW = layoutWinW, winH = layoutWinH;
         CGFloat startX = (screen.frame.size.width - winW) / 2;
         CGFloat startY = (screenH - winH) / 2;
 
@@ -106,7 +186,7 @@ void createAndRunGUI() {
         NSView *content = [mainWindow contentView];
 
         // Alive button — centered
-        CGFloat btnW = 80, btnH = 30;
+        CGFloat btnW = layoutBtnW, btnH = layoutBtnH;
         CGFloat btnX = (winW - btnW) / 2;
         CGFloat btnY = (winH - btnH) / 2;
         aliveButton = [[NSButton alloc] initWithFrame:NSMakeRect(btnX, btnY, btnW, btnH)];
@@ -124,6 +204,16 @@ void createAndRunGUI() {
         [aliveButton setAction:@selector(buttonClicked:)];
         [content addSubview:aliveButton];
 
+        // Mode selector — top edge, between the top button corners
+        modeButton = newSelector(layoutMode, @selector(modeClicked:));
+        applyModeTitle();
+        [content addSubview:modeButton];
+
+        // Timer selector — below the idle button, status line above it
+        timerButton = newSelector(layoutTimer, @selector(timerClicked:));
+        setSelectorTitle(timerButton, timerText);
+        [content addSubview:timerButton];
+
         // Quit hint label — bottom-right corner
         NSTextField *hintLabel = [NSTextField labelWithString:@"To close the App press Cmd+Q"];
         [hintLabel setTextColor:[NSColor colorWithWhite:1.0 alpha:0.35]];
@@ -135,6 +225,14 @@ void createAndRunGUI() {
         [hintLabel setFrameOrigin:NSMakePoint(hintX, hintY)];
         [content addSubview:hintLabel];
 
+        // Status line — above the idle button
+        statusLabel = [NSTextField labelWithString:@""];
+        [statusLabel retain];
+        [statusLabel setTextColor:[NSColor colorWithWhite:0.78 alpha:1.0]];
+        [statusLabel setFont:[NSFont systemFontOfSize:12]];
+        applyStatus();
+        [content addSubview:statusLabel];
+
         // Cmd+Q menu item
         NSMenu *menuBar = [[NSMenu alloc] init];
         NSMenuItem *appMenuItem = [[NSMenuItem alloc] init];
@@ -142,8 +240,9 @@ void createAndRunGUI() {
         NSMenu *appMenu = [[NSMenu alloc] init];
         NSMenuItem *quitItem = [[NSMenuItem alloc]
             initWithTitle:@"Quit Clicky"
-            action:@selector(terminate:)
+            action:@selector(quitClicked:)
             keyEquivalent:@"q"];
+        [quitItem setTarget:btnTarget];
         [appMenu addItem:quitItem];
         [appMenuItem setSubmenu:appMenu];
         [NSApp setMainMenu:menuBar];
@@ -151,6 +250,8 @@ void createAndRunGUI() {
         [mainWindow makeKeyAndOrderFront:nil];
         [NSApp activateIgnoringOtherApps:YES];
 
+        goOnWindowReady();
+
         [NSApp run];
     }
 }
@@ -170,20 +271,73 @@ void macGetCursorPos(int *outX, int *outY) {
     *outY = (int)(screenH - loc.y);
 }
 
-void macClick() {
+void macScreenSize(int *outW, int *outH) {
+    NSRect frame = [[NSScreen mainScreen] frame];
+    *outW = (int)frame.size.width;
+    *outH = (int)frame.size.height;
+}
+
+static CGPoint cursorLocation(void) {
     NSPoint loc = [NSEvent mouseLocation];
-    NSScreen *screen = [NSScreen mainScreen];
-    CGFloat screenH = screen.frame.size.height;
-    CGPoint pt = CGPointMake(loc.x, screenH - loc.y);
+    CGFloat screenH = [NSScreen mainScreen].frame.size.height;
+    return CGPointMake(loc.x, screenH - loc.y);
+}
+
+// button: 0 left, 1 right, 2 middle. clickCount tells apps a second press
+// is a double-click; macOS does not work that out from timing.
+void macMouseButton(int button, int down, int clickCount) {
+    static const CGEventType downTypes[] = {kCGEventLeftMouseDown, kCGEventRightMouseDown, kCGEventOtherMouseDown};
+    static const CGEventType upTypes[]   = {kCGEventLeftMouseUp, kCGEventRightMouseUp, kCGEventOtherMouseUp};
+    static const CGMouseButton buttons[] = {kCGMouseButtonLeft, kCGMouseButtonRight, kCGMouseButtonCenter};
+
+    CGEventType type = down ? downTypes[button] : upTypes[button];
+    CGEventRef ev = CGEventCreateMouseEvent(NULL, type, cursorLocation(), buttons[button]);
+    CGEventSetIntegerValueField(ev, kCGMouseEventClickState, clickCount);
+    CGEventPost(kCGHIDEventTap, ev);
+    CFRelease(ev);
+}
 
-    CGEventRef down = CGEventCreateMouseEvent(NULL, kCGEventLeftMouseDown, pt, kCGMouseButtonLeft);
-    CGEventRef up   = CGEventCreateMouseEvent(NULL, kCGEventLeftMouseUp,   pt, kCGMouseButtonLeft);
+void macScroll(int ticks) {
+    // Positive wheel values scroll up, away from the user
+    CGEventRef ev = CGEventCreateScrollWheelEvent(NULL, kCGScrollEventUnitLine, 1, -ticks);
+    CGEventPost(kCGHIDEventTap, ev);
+    CFRelease(ev);
+}
+
+void macTapKey(int code, int isModifier) {
+    CGEventRef down = CGEventCreateKeyboardEvent(NULL, (CGKeyCode)code, true);
+    CGEventRef up   = CGEventCreateKeyboardEvent(NULL, (CGKeyCode)code, false);
+    if (isModifier) {
+        // A modifier press has to carry its own flag, or apps see nothing
+        CGEventSetFlags(down, kCGEventFlagMaskShift);
+        CGEventSetFlags(up, 0);
+    }
     CGEventPost(kCGHIDEventTap, down);
     CGEventPost(kCGHIDEventTap, up);
     CFRelease(down);
     CFRelease(up);
 }
 
+int macCursorOverButton() {
+    __block int over = 0;
+    dispatch_sync(dispatch_get_main_queue(), ^{
+        NSPoint loc = [NSEvent mouseLocation];
+        // Topmost window at the cursor, across all apps
+        NSInteger num = [NSWindow windowNumberAtPoint:loc belowWindowWithWindowNumber:0];
+        if (num != [mainWindow windowNumber]) {
+            return;
+        }
+        NSPoint winPt = [mainWindow convertPointFromScreen:loc];
+        NSPoint btnPt = [aliveButton convertPoint:winPt fromView:nil];
+        over = NSPointInRect(btnPt, [aliveButton bounds]);
+    });
+    return over;
+}
+
+double macIdleSeconds() {
+    return CGEventSourceSecondsSinceLastEventType(kCGEventSourceStateHIDSystemState, kCGAnyInputEventType);
+}
+
 void macPreventSleep() {
     if (sleepAssertionID == 0) {
         IOPMAssertionCreateWithName(
@@ -240,6 +394,42 @@ void macSetButtonActive(int isActive) {
     });
 }
 
+void macSetModeLabel(const char *text) {
+    // Copy now: Go frees text as soon as we return
+    NSString *str = [[NSString alloc] initWithUTF8String:text];
+    dispatch_async(dispatch_get_main_queue(), ^{
+        [modeText release];
+        modeText = str;
+        if (modeButton) {
+            applyModeTitle();
+        }
+    });
+}
+
+void macSetStatus(const char *text) {
+    // Copy now: Go frees text as soon as we return
+    NSString *str = [[NSString alloc] initWithUTF8String:text];
+    dispatch_async(dispatch_get_main_queue(), ^{
+        [statusText release];
+        statusText = str;
+        if (statusLabel) {
+            applyStatus();
+        }
+    });
+}
+
+void macSetTimerLabel(const char *text) {
+    // Copy now: Go frees text as soon as we return
+    NSString *str = [[NSString alloc] initWithUTF8String:text];
+    dispatch_async(dispatch_get_main_queue(), ^{
+        [timerText release];
+        timerText = str;
+        if (timerButton) {
+            setSelectorTitle(timerButton, timerText);
+        }
+    });
+}
+
 void macReinforceTopmost() {
     dispatch_async(dispatch_get_main_queue(), ^{
         [mainWindow setLevel:NSFloatingWindowLevel];
@@ -248,6 +438,24 @@ void macReinforceTopmost() {
 
 void macQuit() {
     dispatch_async(dispatch_get_main_queue(), ^{
-        [NSApp terminate:nil];
+        [mainWindow orderOut:nil];
+        if (terminateReplyPending) {
+            // The engine has unwound; let the logout or restart go on
+            terminateReplyPending = NO;
+            [NSApp replyToApplicationShouldTerminate:YES];
+            return;
+        }
+        // stop: takes effect after the next event, so post one
+        [NSApp stop:nil];
+        NSEvent *wake = [NSEvent otherEventWithType:NSEventTypeApplicationDefined
+                                           location:NSZeroPoint
+                                      modifierFlags:0
+                                          timestamp:0
+                                       windowNumber:0
+                                            context:nil
+                                            subtype:0
+                                              data1:0
+                                              data2:0];
+        [NSApp postEvent:wake atStart:YES];
     });
 }
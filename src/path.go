@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ── Path generators ─────────────────────────────────────────────────────────
+// A path generator picks the shape of one glide. The path it returns maps
+// t in [0, 1] to a position, starting exactly at from and ending exactly at
+// to; moveCursorAlongCurve decides how t advances. Generators that wander
+// keep inside the screen, of size screen.
+
+const (
+	pathQuadratic  = "quadratic"   // one control point off the line
+	pathCubic      = "cubic"       // two independent control points
+	pathCatmullRom = "catmull-rom" // spline through random waypoints
+	pathStraight   = "straight"    // no bend at all
+	pathTemplate   = "template"    // a recorded stroke of the user's own
+	pathWind       = "wind"        // WindMouse: gravity and random wind
+)
+
+var pathGens = []string{pathQuadratic, pathCubic, pathCatmullRom, pathStraight, pathTemplate, pathWind}
+
+type point struct{ X, Y float64 }
+
+func (p point) add(q point) point      { return point{p.X + q.X, p.Y + q.Y} }
+func (p point) sub(q point) point      { return point{p.X - q.X, p.Y - q.Y} }
+func (p point) scale(f float64) point  { return point{p.X * f, p.Y * f} }
+func (p point) dist(q point) float64   { return math.Hypot(q.X-p.X, q.Y-p.Y) }
+func lerp(p, q point, t float64) point { return p.add(q.sub(p).scale(t)) }
+func (p point) round() (int, int)      { return int(math.Round(p.X)), int(math.Round(p.Y)) }
+func pointOf(x, y int) point           { return point{float64(x), float64(y)} }
+
+// clamp returns p moved into the box lo..hi.
+func (p point) clamp(lo, hi point) point {
+	return point{math.Max(lo.X, math.Min(hi.X, p.X)), math.Max(lo.Y, math.Min(hi.Y, p.Y))}
+}
+
+// onScreen is the box a wandering path keeps to: like overshoot points,
+// overshootEdge inside the screen, or as close to its edge as from and to.
+func onScreen(from, to, screen point) (lo, hi point) {
+	lo = point{math.Min(overshootEdge, math.Min(from.X, to.X)), math.Min(overshootEdge, math.Min(from.Y, to.Y))}
+	hi = point{
+		math.Max(screen.X-1-overshootEdge, math.Max(from.X, to.X)),
+		math.Max(screen.Y-1-overshootEdge, math.Max(from.Y, to.Y)),
+	}
+	return lo, hi
+}
+
+// path maps t in [0, 1] to a position.
+type path func(t float64) point
+
+type pathGen interface {
+	Path(from, to, screen point, r randSource) path
+}
+
+func newPathGen(cc curveConfig) (pathGen, error) {
+	switch cc.Path {
+	case pathQuadratic, "":
+		return quadraticPath{}, nil
+	case pathCubic:
+		return cubicPath{}, nil
+	case pathCatmullRom:
+		if cc.Waypoints < 1 || cc.Waypoints > 10 {
+			return nil, fmt.Errorf("curve: waypoints must be 1..10, got %d", cc.Waypoints)
+		}
+		return catmullRomPath{cc.Waypoints}, nil
+	case pathStraight:
+		return straightPath{}, nil
+	case pathTemplate:
+		lib, err := loadTemplates(cc.templatesPath())
+		if err != nil {
+			return nil, fmt.Errorf("curve: templates: %w", err)
+		}
+		return templatePath{lib}, nil
+	case pathWind:
+		// Weaker gravity or stronger wind can leave the cursor drifting
+		// past windMaxSteps, and the path would jump to the target
+		if cc.Gravity < 1 || cc.Wind < 0 || cc.Wind > 3*cc.Gravity || cc.MaxStepPx < 3 || cc.MaxStepPx > 50 {
+			return nil, fmt.Errorf("curve: wind needs gravity >= 1, wind 0..3*gravity and max_step_px 3..50")
+		}
+		return windPath{cc.Gravity, cc.Wind, cc.MaxStepPx}, nil
+	}
+	return nil, fmt.Errorf("curve: path must be one of %v, got %q", pathGens, cc.Path)
+}
+
+// offLine returns the point a fraction t along from→to, pushed sideways by
+// a random amount of up to ±spread times the distance.
+func offLine(from, to point, t, spread float64, r randSource) point {
+	d := from.dist(to)
+	if d == 0 {
+		return from
+	}
+	perp := point{-(to.Y - from.Y) / d, (to.X - from.X) / d}
+	amplitude := (r.Float64()*2 - 1) * d * spread
+	return lerp(from, to, t).add(perp.scale(amplitude))
+}
+
+type straightPath struct{}
+
+func (straightPath) Path(from, to, _ point, _ randSource) path {
+	return func(t float64) point { return lerp(from, to, t) }
+}
+
+// quadraticPath bends through one control point off the midpoint, by up to
+// a third of the distance either way.
+type quadraticPath struct{}
+
+func (quadraticPath) Path(from, to, _ point, r randSource) path {
+	cp := offLine(from, to, 0.5, 1.0/3, r)
+	return func(t float64) point {
+		// Quadratic Bézier: B(t) = (1-t)²·P0 + 2·(1-t)·t·P1 + t²·P2
+		inv := 1 - t
+		return from.scale(inv * inv).add(cp.scale(2 * inv * t)).add(to.scale(t * t))
+	}
+}
+
+// cubicPath has control points at a third and two thirds of the way, each
+// pushed sideways on its own, so the glide can S-bend.
+type cubicPath struct{}
+
+func (cubicPath) Path(from, to, _ point, r randSource) path {
+	c1 := offLine(from, to, 1.0/3, 1.0/3, r)
+	c2 := offLine(from, to, 2.0/3, 1.0/3, r)
+	return func(t float64) point {
+		// Cubic Bézier: B(t) = (1-t)³·P0 + 3·(1-t)²·t·P1 + 3·(1-t)·t²·P2 + t³·P3
+		inv := 1 - t
+		return from.scale(inv * inv * inv).
+			add(c1.scale(3 * inv * inv * t)).
+			add(c2.scale(3 * inv * t * t)).
+			add(to.scale(t * t * t))
+	}
+}
+
+// catmullRomPath runs a Catmull-Rom spline through n waypoints spread
+// evenly along the line and pushed sideways by up to a quarter of the
+// distance. Each segment gets an equal share of t.
+type catmullRomPath struct{ n int }
+
+func (c catmullRomPath) Path(from, to, _ point, r randSource) path {
+	pts := []point{from, from} // end points doubled so the spline reaches them
+	for i := 1; i <= c.n; i++ {
+		pts = append(pts, offLine(from, to, float64(i)/float64(c.n+1), 0.25, r))
+	}
+	pts = append(pts, to, to)
+
+	segs := len(pts) - 3
+	return func(t float64) point {
+		if t >= 1 {
+			return to
+		}
+		s := int(t * float64(segs))
+		u := t*float64(segs) - float64(s)
+		return catmullRom(pts[s], pts[s+1], pts[s+2], pts[s+3], u)
+	}
+}
+
+// polyline runs through pts in order, giving each segment an equal share
+// of t.
+func polyline(pts []point) path {
+	last := float64(len(pts) - 1)
+	return func(t float64) point {
+		if t >= 1 {
+			return pts[len(pts)-1]
+		}
+		i := int(t * last)
+		return lerp(pts[i], pts[i+1], t*last-float64(i))
+	}
+}
+
+// catmullRom evaluates the uniform Catmull-Rom segment from p1 to p2.
+func catmullRom(p0, p1, p2, p3 point, u float64) point {
+	u2, u3 := u*u, u*u*u
+	return p0.scale(-0.5*u3 + u2 - 0.5*u).
+		add(p1.scale(1.5*u3 - 2.5*u2 + 1)).
+		add(p2.scale(-1.5*u3 + 2*u2 + 0.5*u)).
+		add(p3.scale(0.5*u3 - 0.5*u2))
+}
+
+const (
+	windDamping  = 12    // px from the target where the wind dies down
+	windFriction = 0.95  // share of velocity kept per step; stops orbits
+	windMaxSteps = 20000 // simulation budget; valid settings settle well inside it
+)
+
+// windPath simulates WindMouse: each step the cursor's velocity is pulled
+// towards the target by gravity and pushed about by a random wind, and its
+// speed is capped at maxStep. Near the target the wind dies down and the
+// cap shrinks, so the cursor settles, and a little friction keeps weak
+// gravity from swinging it into orbit. The path runs through the simulated
+// positions, one share of t each.
+type windPath struct{ gravity, wind, maxStep float64 }
+
+func (w windPath) Path(from, to, screen point, r randSource) path {
+	return polyline(append(w.simulate(from, to, screen, r), to))
+}
+
+// simulate returns the positions from from until the cursor is within a
+// pixel of to, or the budget runs out. They stay inside onScreen's box.
+func (w windPath) simulate(from, to, screen point, r randSource) []point {
+	lo, hi := onScreen(from, to, screen)
+
+	sqrt3, sqrt5 := math.Sqrt(3), math.Sqrt(5)
+	pts := []point{from}
+	pos, vel, wind := from, point{}, point{}
+	maxStep, windMag := w.maxStep, w.wind
+	for i := 0; i < windMaxSteps; i++ {
+		d := pos.dist(to)
+		if d < 1 {
+			break
+		}
+		windMag = math.Min(windMag, d)
+		if d >= windDamping {
+			gust := point{r.Float64()*2 - 1, r.Float64()*2 - 1}.scale(windMag)
+			wind = wind.scale(1 / sqrt3).add(gust.scale(1 / sqrt5))
+		} else {
+			wind = wind.scale(1 / sqrt3)
+			if maxStep < 3 {
+				maxStep = 3 + r.Float64()*3
+			} else {
+				maxStep /= sqrt5
+			}
+		}
+		vel = vel.scale(windFriction).add(wind).add(to.sub(pos).scale(w.gravity / d))
+		if v := math.Hypot(vel.X, vel.Y); v > maxStep {
+			clip := maxStep/2 + r.Float64()*maxStep/2
+			vel = vel.scale(clip / v)
+		}
+		pos = pos.add(vel)
+
+		// Stop dead at the edge rather than slide along it
+		if pos.X < lo.X || pos.X > hi.X {
+			pos.X, vel.X = math.Max(lo.X, math.Min(hi.X, pos.X)), 0
+		}
+		if pos.Y < lo.Y || pos.Y > hi.Y {
+			pos.Y, vel.Y = math.Max(lo.Y, math.Min(hi.Y, pos.Y)), 0
+		}
+		pts = append(pts, pos)
+	}
+	return pts
+}
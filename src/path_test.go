@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// testTemplates is a one-stroke library: a flat arc 200 px long.
+var testTemplates = []movementTemplate{{IntervalMs: 8, Points: [][2]int{{0, 0}, {40, 10}, {120, 30}, {200, 0}}}}
+
+// TestPathGens checks every generator against points sampled with a fixed
+// seed, so a change to a generator or to its use of the random source shows
+// up here. The ends must be exact, not just close.
+func TestPathGens(t *testing.T) {
+	from, to, screen := point{100, 200}, point{900, 600}, point{1920, 1080}
+	for _, tc := range []struct {
+		name string
+		gen  pathGen
+		want [3]point // at t = 0.25, 0.5, 0.75
+	}{
+		{pathStraight, straightPath{}, [3]point{{300, 300}, {500, 400}, {700, 500}}},
+		{pathQuadratic, quadraticPath{}, [3]point{{289.5340, 320.9321}, {486.0453, 427.9094}, {689.5340, 520.9321}}},
+		{pathCubic, cubicPath{}, [3]point{{271.7066, 356.5867}, {445.4831, 509.0339}, {646.5180, 606.9641}}},
+		{pathCatmullRom, catmullRomPath{2}, [3]point{{281.7926, 320.7897}, {438.6684, 522.6631}, {631.3210, 652.9830}}},
+		{pathTemplate, templatePath{testTemplates}, [3]point{{206.5836, 286.8328}, {384.2229, 431.5542}, {619.7508, 560.4984}}},
+		{pathWind, windPath{9, 3, 15}, [3]point{{299.7812, 301.6287}, {500.6820, 412.2051}, {733.5988, 520.8302}}},
+	} {
+		p := tc.gen.Path(from, to, screen, rand.New(rand.NewSource(1)))
+		if got := p(0); got != from {
+			t.Errorf("%s: p(0) = %v, want %v", tc.name, got, from)
+		}
+		if got := p(1); got != to {
+			t.Errorf("%s: p(1) = %v, want %v", tc.name, got, to)
+		}
+		for i, u := range []float64{0.25, 0.5, 0.75} {
+			if got := p(u); got.dist(tc.want[i]) > 1e-3 {
+				t.Errorf("%s: p(%v) = %.4f, want %.4f", tc.name, u, got, tc.want[i])
+			}
+		}
+	}
+}
+
+// TestPathGensEnds tries many seeds and distances, down to none at all.
+func TestPathGensEnds(t *testing.T) {
+	gens := []pathGen{straightPath{}, quadraticPath{}, cubicPath{}, catmullRomPath{1}, catmullRomPath{10},
+		templatePath{testTemplates}, windPath{9, 3, 15}, windPath{1, 3, 3}}
+	r, screen := rand.New(rand.NewSource(2)), point{1920, 1080}
+	for i := 0; i < 200; i++ {
+		from := point{math.Round(r.Float64() * 1920), math.Round(r.Float64() * 1080)}
+		to := lerp(from, point{960, 540}, float64(i%5)/4) // i%5 == 0: from == to
+		for _, g := range gens {
+			p := g.Path(from, to, screen, r)
+			if p(0) != from || p(1) != to {
+				t.Fatalf("%T %v → %v: runs %v → %v", g, from, to, p(0), p(1))
+			}
+		}
+	}
+}
+
+// TestWindSettles runs the wind path at the edges of its valid settings,
+// across a 4K screen and along its edges. Every run must settle on the
+// target before windMaxSteps and never leave the screen's inner band.
+func TestWindSettles(t *testing.T) {
+	screen := point{3840, 2160}
+	far := point{screen.X - 1 - overshootEdge, screen.Y - 1 - overshootEdge}
+	pairs := [][2]point{
+		{{overshootEdge, overshootEdge}, far},
+		{far, {overshootEdge, overshootEdge}},
+		{{overshootEdge, far.Y}, {far.X, far.Y}}, // along the bottom edge
+		{{0, 0}, {screen.X - 1, screen.Y - 1}},   // from the very corners
+	}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 12; i++ {
+		pairs = append(pairs, [2]point{
+			{math.Round(r.Float64() * (screen.X - 1)), math.Round(r.Float64() * (screen.Y - 1))},
+			{math.Round(r.Float64() * (screen.X - 1)), math.Round(r.Float64() * (screen.Y - 1))},
+		})
+	}
+	for _, w := range []windPath{{1, 0, 3}, {1, 3, 3}, {1, 3, 50}, {9, 3, 15}, {9, 27, 3}, {50, 150, 3}, {50, 150, 50}} {
+		for _, pair := range pairs {
+			from, to := pair[0], pair[1]
+			lo := point{math.Min(overshootEdge, math.Min(from.X, to.X)), math.Min(overshootEdge, math.Min(from.Y, to.Y))}
+			hi := point{math.Max(far.X, math.Max(from.X, to.X)), math.Max(far.Y, math.Max(from.Y, to.Y))}
+			for seed := int64(0); seed < 5; seed++ {
+				pts := w.simulate(from, to, screen, rand.New(rand.NewSource(seed)))
+				if len(pts) > windMaxSteps {
+					t.Fatalf("%v %v → %v seed %d: ran out of steps", w, from, to, seed)
+				}
+				if d := pts[len(pts)-1].dist(to); d >= 1 {
+					t.Errorf("%v %v → %v seed %d: stopped %.1f px short", w, from, to, seed, d)
+				}
+				for _, q := range pts {
+					if q.X < lo.X || q.Y < lo.Y || q.X > hi.X || q.Y > hi.Y {
+						t.Fatalf("%v %v → %v seed %d: left the screen at %.1f", w, from, to, seed, q)
+					}
+				}
+				if got := w.Path(from, to, screen, rand.New(rand.NewSource(seed)))(1); got != to {
+					t.Errorf("%v %v → %v seed %d: ends at %v", w, from, to, seed, got)
+				}
+			}
+		}
+	}
+}
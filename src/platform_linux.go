@@ -0,0 +1,207 @@
+//go:build linux
+
+package main
+
+/*
+#cgo LDFLAGS: -lX11 -lXtst -lXss
+#include <stdlib.h>
+#include "x11_linux.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// ── Go exports for C callbacks ──────────────────────────────────────────────
+
+//export goOnButtonClicked
+func goOnButtonClicked() {
+	if onButtonClicked != nil {
+		onButtonClicked()
+	}
+}
+
+//export goOnHotkeyQuit
+func goOnHotkeyQuit() {
+	if onHotkeyQuit != nil {
+		onHotkeyQuit()
+	}
+}
+
+//export goOnModeClicked
+func goOnModeClicked() {
+	if onModeClicked != nil {
+		onModeClicked()
+	}
+}
+
+//export goOnTimerClicked
+func goOnTimerClicked() {
+	if onTimerClicked != nil {
+		onTimerClicked()
+	}
+}
+
+//export goOnWindowReady
+func goOnWindowReady() {
+	if onWindowReady != nil {
+		onWindowReady()
+	}
+}
+
+// ── Platform interface implementation ───────────────────────────────────────
+
+// x11Platform is the X11 backend (XTest + MIT-SCREEN-SAVER). Sleep is
+// inhibited over D-Bus when logind or a ScreenSaver service is available,
+// with the X11 screensaver extension as the fallback.
+type x11Platform struct {
+	sleep      *dbusInhibitor
+	x11Suspend bool      // X11 fallback in use
+	keyMissing sync.Once // reports a key the keymap cannot type
+}
+
+func newPlatform(win windowConfig) Platform {
+	if C.x11Open() == 0 {
+		fmt.Fprintln(os.Stderr, "clicky: cannot open X display (is DISPLAY set?)")
+		os.Exit(1)
+	}
+	mr, tr := win.modeRect(), win.timerRect()
+	C.x11SetLayout(C.int(win.Width), C.int(win.Height), C.int(win.ButtonWidth), C.int(win.ButtonHeight),
+		C.int(mr[0]), C.int(mr[1]), C.int(mr[2]), C.int(mr[3]),
+		C.int(tr[0]), C.int(tr[1]), C.int(tr[2]), C.int(tr[3]), C.int(win.statusRect()[1]))
+	x := &x11Platform{sleep: newDBusInhibitor()}
+	if !isWaylandSession() {
+		return x
+	}
+	// Cursor warping is a no-op under Wayland; fail loudly instead
+	w, err := newWaylandPlatform(x)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clicky: Wayland session:", err)
+		os.Exit(1)
+	}
+	return w
+}
+
+func (p *x11Platform) Run() {
+	C.x11RunGUI()
+}
+
+func (p *x11Platform) SetCursorPos(x, y int) {
+	C.x11SetCursorPos(C.int(x), C.int(y))
+}
+
+func (p *x11Platform) GetCursorPos() (int, int) {
+	var ox, oy C.int
+	C.x11GetCursorPos(&ox, &oy)
+	return int(ox), int(oy)
+}
+
+func (p *x11Platform) ScreenSize() (int, int) {
+	var ow, oh C.int
+	C.x11ScreenSize(&ow, &oh)
+	return int(ow), int(oh)
+}
+
+// X button numbers; X derives double-clicks from timing, so count is unused
+var x11Buttons = map[mouseButton]C.uint{buttonLeft: 1, buttonMiddle: 2, buttonRight: 3}
+
+func (p *x11Platform) MouseDown(b mouseButton, count int) {
+	C.x11MouseButton(x11Buttons[b], 1)
+}
+
+func (p *x11Platform) MouseUp(b mouseButton, count int) {
+	C.x11MouseButton(x11Buttons[b], 0)
+}
+
+func (p *x11Platform) Scroll(ticks int) {
+	C.x11Scroll(C.int(ticks))
+}
+
+// Keysyms from X11/keysymdef.h
+var x11Keysyms = map[key]C.ulong{keyF15: 0xFFCC, keyShift: 0xFFE1}
+
+// XKB key names to fall back on when no key carries the keysym
+var x11KeyNames = map[key]string{keyF15: "FK15", keyShift: "LFSH"}
+
+func (p *x11Platform) TapKey(k key) {
+	name := C.CString(x11KeyNames[k])
+	defer C.free(unsafe.Pointer(name))
+	if C.x11TapKey(x11Keysyms[k], name) == 0 {
+		p.keyMissing.Do(func() {
+			fmt.Fprintf(os.Stderr, "clicky: the X keymap has no key for %s; key taps do nothing\n", k)
+		})
+	}
+}
+
+func (p *x11Platform) CursorOverButton() bool {
+	return C.x11CursorOverButton() != 0
+}
+
+func (p *x11Platform) IdleTime() time.Duration {
+	return time.Duration(C.x11IdleMillis()) * time.Millisecond
+}
+
+func (p *x11Platform) PreventSleep() {
+	if p.sleep.Prevent() {
+		return
+	}
+	p.x11Suspend = true
+	C.x11PreventSleep()
+}
+
+func (p *x11Platform) AllowSleep() {
+	p.sleep.Allow()
+	if p.x11Suspend {
+		p.x11Suspend = false
+		C.x11AllowSleep()
+	}
+}
+
+func (p *x11Platform) MoveButton(x, y int) {
+	C.x11MoveButton(C.int(x), C.int(y))
+}
+
+func (p *x11Platform) ClientToScreen(x, y int) (int, int) {
+	var ox, oy C.int
+	C.x11ClientToScreen(C.int(x), C.int(y), &ox, &oy)
+	return int(ox), int(oy)
+}
+
+func (p *x11Platform) SetButtonActive(isActive bool) {
+	v := C.int(0)
+	if isActive {
+		v = 1
+	}
+	C.x11SetButtonActive(v)
+}
+
+func (p *x11Platform) SetModeLabel(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.x11SetModeLabel(cs)
+}
+
+func (p *x11Platform) SetStatus(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.x11SetStatus(cs)
+}
+
+func (p *x11Platform) SetTimerLabel(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.x11SetTimerLabel(cs)
+}
+
+func (p *x11Platform) ReinforceTopmost() {
+	C.x11ReinforceTopmost()
+}
+
+func (p *x11Platform) Quit() {
+	C.x11Quit()
+}
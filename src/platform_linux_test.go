@@ -0,0 +1,72 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestX11Cursor runs the real X11 backend, so it needs a server; under Xvfb:
+//
+//	xvfb-run go test -run X11 .
+func TestX11Cursor(t *testing.T) {
+	if os.Getenv("DISPLAY") == "" {
+		t.Skip("DISPLAY is not set")
+	}
+	if isWaylandSession() {
+		t.Skip("Wayland session; the cursor is driven through uinput")
+	}
+	win := defaultConfig().Window
+	p := newPlatform(win)
+
+	errs := make(chan string, 8)
+	onWindowReady = func() {
+		go func() {
+			defer p.Quit()
+			defer close(errs)
+			checkX11Cursor(p, win, errs)
+		}()
+	}
+	defer func() { onWindowReady = nil }()
+	p.Run()
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+func checkX11Cursor(p Platform, win windowConfig, errs chan<- string) {
+	w, h := p.ScreenSize()
+	if w <= 0 || h <= 0 {
+		errs <- "screen size is empty"
+		return
+	}
+	p.SetCursorPos(w/3, h/3)
+	if x, y := p.GetCursorPos(); x != w/3 || y != h/3 {
+		errs <- fmt.Sprintf("cursor warped to %d,%d, want %d,%d", x, y, w/3, h/3)
+	}
+
+	// The window maps asynchronously; retry until the button is under the cursor
+	c := win.corners()[3]
+	p.MoveButton(c[0], c[1])
+	over := false
+	for deadline := time.Now().Add(2 * time.Second); !over && time.Now().Before(deadline); {
+		sx, sy := p.ClientToScreen(c[0]+win.ButtonWidth/2, c[1]+win.ButtonHeight/2)
+		p.SetCursorPos(sx, sy)
+		if over = p.CursorOverButton(); !over {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if !over {
+		errs <- "cursor on the Alive button centre, but CursorOverButton is false"
+	}
+
+	// Just off the button, still inside the window
+	sx, sy := p.ClientToScreen(c[0]-1, c[1]+win.ButtonHeight/2)
+	p.SetCursorPos(sx, sy)
+	if p.CursorOverButton() {
+		errs <- "cursor left of the Alive button, but CursorOverButton is true"
+	}
+}
@@ -4,11 +4,15 @@ package main
 
 /*
 #cgo LDFLAGS: -framework Cocoa -framework CoreGraphics -framework IOKit
+#include <stdlib.h>
 #include "objc_darwin.h"
 */
 import "C"
 
-import "unsafe"
+import (
+	"time"
+	"unsafe"
+)
 
 // ── Go exports for Objective-C callbacks ────────────────────────────────────
 
@@ -26,46 +30,111 @@ func goOnHotkeyQuit() {
 	}
 }
 
+//export goOnModeClicked
+func goOnModeClicked() {
+	if onModeClicked != nil {
+		onModeClicked()
+	}
+}
+
+//export goOnTimerClicked
+func goOnTimerClicked() {
+	if onTimerClicked != nil {
+		onTimerClicked()
+	}
+}
+
+//export goOnWindowReady
+func goOnWindowReady() {
+	if onWindowReady != nil {
+		onWindowReady()
+	}
+}
+
 // ── Platform interface implementation ───────────────────────────────────────
 
-func platformRun() {
+// macPlatform is the Cocoa backend.
+type macPlatform struct{}
+
+func newPlatform(win windowConfig) Platform {
+	mr, tr := win.modeRect(), win.timerRect()
+	C.macSetLayout(C.int(win.Width), C.int(win.Height), C.int(win.ButtonWidth), C.int(win.ButtonHeight),
+		C.int(mr[0]), C.int(mr[1]), C.int(mr[2]), C.int(mr[3]),
+		C.int(tr[0]), C.int(tr[1]), C.int(tr[2]), C.int(tr[3]), C.int(win.statusRect()[1]))
+	return macPlatform{}
+}
+
+func (macPlatform) Run() {
 	C.setIconData(unsafe.Pointer(&iconPNG[0]), C.int(len(iconPNG)))
 	C.createAndRunGUI()
 }
 
-func platformSetCursorPos(x, y int) {
+func (macPlatform) SetCursorPos(x, y int) {
 	C.macSetCursorPos(C.int(x), C.int(y))
 }
 
-func platformGetCursorPos() (int, int) {
+func (macPlatform) GetCursorPos() (int, int) {
 	var ox, oy C.int
 	C.macGetCursorPos(&ox, &oy)
 	return int(ox), int(oy)
 }
 
-func platformClick() {
-	C.macClick()
+func (macPlatform) ScreenSize() (int, int) {
+	var ow, oh C.int
+	C.macScreenSize(&ow, &oh)
+	return int(ow), int(oh)
+}
+
+func (macPlatform) MouseDown(b mouseButton, count int) {
+	C.macMouseButton(C.int(b), 1, C.int(count))
+}
+
+func (macPlatform) MouseUp(b mouseButton, count int) {
+	C.macMouseButton(C.int(b), 0, C.int(count))
 }
 
-func platformPreventSleep() {
+func (macPlatform) Scroll(ticks int) {
+	C.macScroll(C.int(ticks))
+}
+
+// Virtual key codes from HIToolbox/Events.h
+var macKeyCodes = map[key]C.int{keyF15: 0x71, keyShift: 0x38}
+
+func (macPlatform) TapKey(k key) {
+	mod := C.int(0)
+	if k == keyShift {
+		mod = 1
+	}
+	C.macTapKey(macKeyCodes[k], mod)
+}
+
+func (macPlatform) CursorOverButton() bool {
+	return C.macCursorOverButton() != 0
+}
+
+func (macPlatform) IdleTime() time.Duration {
+	return time.Duration(float64(C.macIdleSeconds()) * float64(time.Second))
+}
+
+func (macPlatform) PreventSleep() {
 	C.macPreventSleep()
 }
 
-func platformAllowSleep() {
+func (macPlatform) AllowSleep() {
 	C.macAllowSleep()
 }
 
-func platformMoveButton(x, y int) {
+func (macPlatform) MoveButton(x, y int) {
 	C.macMoveButton(C.int(x), C.int(y))
 }
 
-func platformClientToScreen(x, y int) (int, int) {
+func (macPlatform) ClientToScreen(x, y int) (int, int) {
 	var ox, oy C.int
 	C.macClientToScreen(C.int(x), C.int(y), &ox, &oy)
 	return int(ox), int(oy)
 }
 
-func platformSetButtonActive(isActive bool) {
+func (macPlatform) SetButtonActive(isActive bool) {
 	v := C.int(0)
 	if isActive {
 		v = 1
@@ -73,10 +142,28 @@ func platformSetButtonActive(isActive bool) {
 	C.macSetButtonActive(v)
 }
 
-func platformReinforceTopmost() {
+func (macPlatform) SetModeLabel(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.macSetModeLabel(cs)
+}
+
+func (macPlatform) SetStatus(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.macSetStatus(cs)
+}
+
+func (macPlatform) SetTimerLabel(text string) {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	C.macSetTimerLabel(cs)
+}
+
+func (macPlatform) ReinforceTopmost() {
 	C.macReinforceTopmost()
 }
 
-func platformQuit() {
+func (macPlatform) Quit() {
 	C.macQuit()
 }
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// ── Recording backend ───────────────────────────────────────────────────────
+// recordPlatform is an in-memory Platform that records every call instead of
+// touching the real cursor, window or power management. It has no GUI, so the
+// alive engine can run against it on machines without a display.
+
+// Recorded event kinds
+const (
+	evSetCursor    = "cursor"
+	evMouseDown    = "down"
+	evMouseUp      = "up"
+	evScroll       = "scroll"
+	evKey          = "key"
+	evMoveButton   = "button"
+	evButtonActive = "active"
+	evModeLabel    = "mode"
+	evStatus       = "status"
+	evTimerLabel   = "timer"
+	evTopmost      = "topmost"
+	evPreventSleep = "prevent-sleep"
+	evAllowSleep   = "allow-sleep"
+	evQuit         = "quit"
+)
+
+type recordedEvent struct {
+	At   time.Duration // since the backend was created
+	Kind string
+	X, Y int    // cursor or button position, if any
+	On   bool   // button state for evButtonActive
+	Text string // label for evModeLabel, evStatus and evTimerLabel; key for evKey
+
+	Button mouseButton // for evMouseDown and evMouseUp
+	N      int         // click count for evMouseDown and evMouseUp; ticks for evScroll
+}
+
+type recordPlatform struct {
+	originX, originY int // screen position of the client area
+	screenW, screenH int
+	btnW, btnH       int
+
+	mu       sync.Mutex
+	start    time.Time
+	curX     int
+	curY     int
+	btnX     int // button position (client coords)
+	btnY     int
+	covered  bool      // another window is over the button
+	input    time.Time // last simulated user input
+	events   []recordedEvent
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newRecordPlatform(win windowConfig, originX, originY int) *recordPlatform {
+	return &recordPlatform{
+		originX: originX,
+		originY: originY,
+		btnW:    win.ButtonWidth,
+		btnH:    win.ButtonHeight,
+		btnX:    (win.Width - win.ButtonWidth) / 2,
+		btnY:    (win.Height - win.ButtonHeight) / 2,
+		screenW: 1920,
+		screenH: 1080,
+		curX:    960, // cursor starts mid-screen, clear of the fail-safe corner
+		curY:    540,
+		start:   time.Now(),
+		input:   time.Now(),
+		quit:    make(chan struct{}),
+	}
+}
+
+func (r *recordPlatform) record(ev recordedEvent) {
+	r.mu.Lock()
+	ev.At = time.Since(r.start)
+	r.events = append(r.events, ev)
+	r.mu.Unlock()
+}
+
+// Events returns a copy of everything recorded so far.
+func (r *recordPlatform) Events() []recordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]recordedEvent, len(r.events))
+	copy(out, r.events)
+	return out
+}
+
+// EventsOf returns the recorded events of one kind, in order.
+func (r *recordPlatform) EventsOf(kind string) []recordedEvent {
+	var out []recordedEvent
+	for _, ev := range r.Events() {
+		if ev.Kind == kind {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// SimulateUserInput pretends the user just touched the mouse or keyboard.
+func (r *recordPlatform) SimulateUserInput() {
+	r.mu.Lock()
+	r.input = time.Now()
+	r.mu.Unlock()
+}
+
+// SimulateUserMove moves the cursor as the user would: it is not recorded
+// as one of our calls and counts as user input.
+func (r *recordPlatform) SimulateUserMove(x, y int) {
+	r.mu.Lock()
+	r.curX, r.curY = x, y
+	r.input = time.Now()
+	r.mu.Unlock()
+}
+
+// SimulateCovered pretends another window is (or is no longer) on top of
+// the button.
+func (r *recordPlatform) SimulateCovered(covered bool) {
+	r.mu.Lock()
+	r.covered = covered
+	r.mu.Unlock()
+}
+
+// ── Platform interface implementation ───────────────────────────────────────
+
+// Run blocks until Quit is called.
+func (r *recordPlatform) Run() {
+	if onWindowReady != nil {
+		onWindowReady()
+	}
+	<-r.quit
+}
+
+func (r *recordPlatform) SetCursorPos(x, y int) {
+	r.mu.Lock()
+	r.curX, r.curY = x, y
+	r.mu.Unlock()
+	r.record(recordedEvent{Kind: evSetCursor, X: x, Y: y})
+}
+
+func (r *recordPlatform) GetCursorPos() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.curX, r.curY
+}
+
+// IdleTime only counts SimulateUserInput; our own calls never reset it.
+func (r *recordPlatform) IdleTime() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Since(r.input)
+}
+
+func (r *recordPlatform) ScreenSize() (int, int) {
+	return r.screenW, r.screenH
+}
+
+func (r *recordPlatform) MouseDown(b mouseButton, count int) {
+	x, y := r.GetCursorPos()
+	r.record(recordedEvent{Kind: evMouseDown, X: x, Y: y, Button: b, N: count})
+}
+
+func (r *recordPlatform) MouseUp(b mouseButton, count int) {
+	x, y := r.GetCursorPos()
+	r.record(recordedEvent{Kind: evMouseUp, X: x, Y: y, Button: b, N: count})
+}
+
+func (r *recordPlatform) Scroll(ticks int) {
+	x, y := r.GetCursorPos()
+	r.record(recordedEvent{Kind: evScroll, X: x, Y: y, N: ticks})
+}
+
+func (r *recordPlatform) TapKey(k key) {
+	r.record(recordedEvent{Kind: evKey, Text: string(k)})
+}
+
+func (r *recordPlatform) PreventSleep() {
+	r.record(recordedEvent{Kind: evPreventSleep})
+}
+
+func (r *recordPlatform) AllowSleep() {
+	r.record(recordedEvent{Kind: evAllowSleep})
+}
+
+func (r *recordPlatform) CursorOverButton() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	x := r.curX - r.originX - r.btnX
+	y := r.curY - r.originY - r.btnY
+	return !r.covered && x >= 0 && x < r.btnW && y >= 0 && y < r.btnH
+}
+
+func (r *recordPlatform) MoveButton(x, y int) {
+	r.mu.Lock()
+	r.btnX, r.btnY = x, y
+	r.mu.Unlock()
+	r.record(recordedEvent{Kind: evMoveButton, X: x, Y: y})
+}
+
+func (r *recordPlatform) ClientToScreen(x, y int) (int, int) {
+	return r.originX + x, r.originY + y
+}
+
+func (r *recordPlatform) SetButtonActive(isActive bool) {
+	r.record(recordedEvent{Kind: evButtonActive, On: isActive})
+}
+
+func (r *recordPlatform) SetModeLabel(text string) {
+	r.record(recordedEvent{Kind: evModeLabel, Text: text})
+}
+
+func (r *recordPlatform) SetStatus(text string) {
+	r.record(recordedEvent{Kind: evStatus, Text: text})
+}
+
+func (r *recordPlatform) SetTimerLabel(text string) {
+	r.record(recordedEvent{Kind: evTimerLabel, Text: text})
+}
+
+func (r *recordPlatform) ReinforceTopmost() {
+	r.record(recordedEvent{Kind: evTopmost})
+}
+
+func (r *recordPlatform) Quit() {
+	r.record(recordedEvent{Kind: evQuit})
+	r.quitOnce.Do(func() { close(r.quit) })
+}
@@ -7,7 +7,9 @@ import (
 	"image"
 	"image/draw"
 	"image/png"
+	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 
 	_ "image/png"
@@ -31,6 +33,7 @@ const (
 	SW_SHOW = 5
 
 	WM_DESTROY        = 0x0002
+	WM_CLOSE          = 0x0010
 	WM_ERASEBKGND     = 0x0014
 	WM_DRAWITEM        = 0x002B
 	WM_SETICON         = 0x0080
@@ -49,8 +52,22 @@ const (
 
 	SPI_GETWORKAREA = 0x0030
 
-	MOUSEEVENTF_LEFTDOWN = 0x0002
-	MOUSEEVENTF_LEFTUP   = 0x0004
+	SM_CXSCREEN = 0
+	SM_CYSCREEN = 1
+
+	INPUT_MOUSE            = 0
+	INPUT_KEYBOARD         = 1
+	MOUSEEVENTF_LEFTDOWN   = 0x0002
+	MOUSEEVENTF_LEFTUP     = 0x0004
+	MOUSEEVENTF_RIGHTDOWN  = 0x0008
+	MOUSEEVENTF_RIGHTUP    = 0x0010
+	MOUSEEVENTF_MIDDLEDOWN = 0x0020
+	MOUSEEVENTF_MIDDLEUP   = 0x0040
+	MOUSEEVENTF_WHEEL      = 0x0800
+	WHEEL_DELTA            = 120
+	KEYEVENTF_KEYUP        = 0x0002
+	VK_SHIFT               = 0x10
+	VK_F15                 = 0x7E
 
 	ES_CONTINUOUS       = 0x80000000
 	ES_DISPLAY_REQUIRED = 0x00000002
@@ -74,8 +91,10 @@ const (
 	FW_SEMIBOLD   = 600
 	FW_NORMAL     = 400
 
-	BTN_ID  = 1
-	HK_QUIT = 1
+	BTN_ID   = 1
+	MODE_ID  = 2
+	TIMER_ID = 3
+	HK_QUIT  = 1
 )
 
 // ── Win32 types ─────────────────────────────────────────────────────────────
@@ -124,6 +143,41 @@ type DRAWITEMSTRUCT struct {
 	ItemData   uintptr
 }
 
+type LASTINPUTINFO struct {
+	CbSize uint32
+	DwTime uint32
+}
+
+type MOUSEINPUT struct {
+	Dx          int32
+	Dy          int32
+	MouseData   uint32
+	DwFlags     uint32
+	Time        uint32
+	DwExtraInfo uintptr
+}
+
+type KEYBDINPUT struct {
+	WVk         uint16
+	WScan       uint16
+	DwFlags     uint32
+	Time        uint32
+	DwExtraInfo uintptr
+}
+
+// MOUSE_INPUT and KEYBD_INPUT are INPUT with one member of its union each.
+// MOUSEINPUT is the largest member; the padding brings KEYBD_INPUT up to it.
+type MOUSE_INPUT struct {
+	Type uint32
+	Mi   MOUSEINPUT
+}
+
+type KEYBD_INPUT struct {
+	Type uint32
+	Ki   KEYBDINPUT
+	_    [8]byte
+}
+
 type ICONINFO struct {
 	FIcon    uint32
 	XHotspot uint32
@@ -146,7 +200,6 @@ var (
 	pTranslateMessage        = user32.NewProc("TranslateMessage")
 	pDispatchMessageW        = user32.NewProc("DispatchMessageW")
 	pPostQuitMessage         = user32.NewProc("PostQuitMessage")
-	pDestroyWindow           = user32.NewProc("DestroyWindow")
 	pShowWindow              = user32.NewProc("ShowWindow")
 	pUpdateWindow            = user32.NewProc("UpdateWindow")
 	pMoveWindow              = user32.NewProc("MoveWindow")
@@ -158,15 +211,20 @@ var (
 	pAdjustWindowRectEx      = user32.NewProc("AdjustWindowRectEx")
 	pSystemParametersInfoW   = user32.NewProc("SystemParametersInfoW")
 	pSetWindowTextW          = user32.NewProc("SetWindowTextW")
-	pMouseEvent              = user32.NewProc("mouse_event")
+	pSendInput               = user32.NewProc("SendInput")
 	pGetModuleHandleW        = kernel32.NewProc("GetModuleHandleW")
 	pSetThreadExecutionState = kernel32.NewProc("SetThreadExecutionState")
 	pSetProcessDPIAware      = user32.NewProc("SetProcessDPIAware")
 	pGetCursorPos            = user32.NewProc("GetCursorPos")
+	pWindowFromPoint         = user32.NewProc("WindowFromPoint")
+	pGetLastInputInfo        = user32.NewProc("GetLastInputInfo")
+	pGetSystemMetrics        = user32.NewProc("GetSystemMetrics")
+	pGetTickCount            = kernel32.NewProc("GetTickCount")
 	pFillRect                = user32.NewProc("FillRect")
 	pDrawTextW               = user32.NewProc("DrawTextW")
 	pCreateIconIndirect      = user32.NewProc("CreateIconIndirect")
 	pSendMessageW            = user32.NewProc("SendMessageW")
+	pPostMessageW            = user32.NewProc("PostMessageW")
 	pGetClientRect           = user32.NewProc("GetClientRect")
 	pInvalidateRect          = user32.NewProc("InvalidateRect")
 	pSetWindowPos            = user32.NewProc("SetWindowPos")
@@ -183,14 +241,23 @@ var (
 // ── Globals ─────────────────────────────────────────────────────────────────
 
 var (
-	hWndMain syscall.Handle
-	hWndBtn  syscall.Handle
+	hWndMain  syscall.Handle
+	hWndBtn   syscall.Handle
+	hWndMode  syscall.Handle
+	hWndTimer syscall.Handle
+
+	textMu     sync.Mutex
+	modeText   = "" // mode selector label, set from any goroutine
+	statusText = "" // status line, set from any goroutine
+	timerText  = "" // timer selector label, set from any goroutine
+	statusRC   RECT // where the status line goes
 
 	hFont       syscall.Handle
 	hFontHint   syscall.Handle
 	hBrushBg    syscall.Handle // #2B2B2B dark background
 	hBrushBlue  syscall.Handle // #0078D4 button idle
 	hBrushGreen syscall.Handle // #107C10 button active
+	hBrushMode  syscall.Handle // #3C3C3C mode selector
 )
 
 // ── Helpers ─────────────────────────────────────────────────────────────────
@@ -324,6 +391,20 @@ func wndProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 			uintptr(unsafe.Pointer(&hintRC)),
 			DT_RIGHT|DT_BOTTOM|DT_SINGLELINE,
 		)
+		// Status line above the idle button
+		textMu.Lock()
+		status := statusText
+		textMu.Unlock()
+		if status != "" {
+			pSetTextColor.Call(wParam, rgb(0xC8, 0xC8, 0xC8))
+			pDrawTextW.Call(
+				wParam,
+				uintptr(unsafe.Pointer(utf16(status))),
+				uintptr(uint32(0xFFFFFFFF)),
+				uintptr(unsafe.Pointer(&statusRC)),
+				DT_CENTER|DT_VCENTER|DT_SINGLELINE,
+			)
+		}
 		return 1
 
 	case WM_CTLCOLORBTN:
@@ -331,6 +412,27 @@ func wndProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 
 	case WM_DRAWITEM:
 		di := (*DRAWITEMSTRUCT)(unsafe.Pointer(lParam))
+		if di.CtlID == MODE_ID || di.CtlID == TIMER_ID {
+			textMu.Lock()
+			label := modeText
+			if di.CtlID == TIMER_ID {
+				label = timerText
+			}
+			textMu.Unlock()
+			pFillRect.Call(uintptr(di.HDC), uintptr(unsafe.Pointer(&di.RcItem)), uintptr(hBrushMode))
+			pSetBkMode.Call(uintptr(di.HDC), TRANSPARENT)
+			pSetTextColor.Call(uintptr(di.HDC), rgb(0xC8, 0xC8, 0xC8))
+			pSelectObject.Call(uintptr(di.HDC), uintptr(hFontHint))
+			t := utf16(label)
+			pDrawTextW.Call(
+				uintptr(di.HDC),
+				uintptr(unsafe.Pointer(t)),
+				uintptr(uint32(0xFFFFFFFF)), // -1
+				uintptr(unsafe.Pointer(&di.RcItem)),
+				DT_CENTER|DT_VCENTER|DT_SINGLELINE,
+			)
+			return 1
+		}
 		brush := hBrushBlue
 		text := "Alive"
 		if active.Load() {
@@ -357,6 +459,16 @@ func wndProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 				onButtonClicked()
 			}
 		}
+		if hiword(wParam) == BN_CLICKED && loword(wParam) == MODE_ID {
+			if onModeClicked != nil {
+				onModeClicked()
+			}
+		}
+		if hiword(wParam) == BN_CLICKED && loword(wParam) == TIMER_ID {
+			if onTimerClicked != nil {
+				onTimerClicked()
+			}
+		}
 		return 0
 
 	case WM_HOTKEY:
@@ -376,6 +488,7 @@ func wndProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 		pDeleteObject.Call(uintptr(hBrushBg))
 		pDeleteObject.Call(uintptr(hBrushBlue))
 		pDeleteObject.Call(uintptr(hBrushGreen))
+		pDeleteObject.Call(uintptr(hBrushMode))
 		pPostQuitMessage.Call(0)
 		return 0
 	}
@@ -386,7 +499,14 @@ func wndProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 
 // ── Platform interface implementation ───────────────────────────────────────
 
-func platformRun() {
+// winPlatform is the Win32 backend.
+type winPlatform struct {
+	win windowConfig
+}
+
+func newPlatform(win windowConfig) Platform { return winPlatform{win: win} }
+
+func (p winPlatform) Run() {
 	pSetProcessDPIAware.Call()
 
 	// Create GDI resources
@@ -395,6 +515,7 @@ func platformRun() {
 	hBrushBg = winCreateSolidBrush(rgb(0x2B, 0x2B, 0x2B))
 	hBrushBlue = winCreateSolidBrush(rgb(0x00, 0x78, 0xD4))
 	hBrushGreen = winCreateSolidBrush(rgb(0x10, 0x7C, 0x10))
+	hBrushMode = winCreateSolidBrush(rgb(0x3C, 0x3C, 0x3C))
 
 	hIcon := createAppIcon()
 
@@ -420,7 +541,7 @@ func platformRun() {
 	style := uint32(WS_CAPTION | WS_SYSMENU)
 	exStyle := uint32(WS_EX_TOPMOST)
 
-	rc := RECT{0, 0, clientW, clientH}
+	rc := RECT{0, 0, int32(p.win.Width), int32(p.win.Height)}
 	pAdjustWindowRectEx.Call(
 		uintptr(unsafe.Pointer(&rc)),
 		uintptr(style), 0, uintptr(exStyle),
@@ -450,27 +571,66 @@ func platformRun() {
 	pSendMessageW.Call(uintptr(hWndMain), WM_SETICON, ICON_BIG, uintptr(hIcon))
 
 	// Create button — starts at center of client area
-	btnStartX := (clientW - btnW) / 2
-	btnStartY := (clientH - btnH) / 2
+	btnStartX := (p.win.Width - p.win.ButtonWidth) / 2
+	btnStartY := (p.win.Height - p.win.ButtonHeight) / 2
 	btn, _, _ := pCreateWindowExW.Call(
 		0,
 		uintptr(unsafe.Pointer(utf16("BUTTON"))),
 		uintptr(unsafe.Pointer(utf16("Alive"))),
 		uintptr(WS_CHILD|WS_VISIBLE|WS_TABSTOP|BS_OWNERDRAW),
 		uintptr(btnStartX), uintptr(btnStartY),
-		btnW, btnH,
+		uintptr(p.win.ButtonWidth), uintptr(p.win.ButtonHeight),
 		uintptr(hWndMain),
 		BTN_ID,
 		hInst, 0,
 	)
 	hWndBtn = syscall.Handle(btn)
 
+	// Mode selector — top edge, between the top button corners
+	mr := p.win.modeRect()
+	textMu.Lock()
+	modeLabel := modeText
+	textMu.Unlock()
+	mode, _, _ := pCreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(utf16("BUTTON"))),
+		uintptr(unsafe.Pointer(utf16(modeLabel))),
+		uintptr(WS_CHILD|WS_VISIBLE|WS_TABSTOP|BS_OWNERDRAW),
+		uintptr(mr[0]), uintptr(mr[1]),
+		uintptr(mr[2]), uintptr(mr[3]),
+		uintptr(hWndMain),
+		MODE_ID,
+		hInst, 0,
+	)
+	hWndMode = syscall.Handle(mode)
+
+	// Timer selector — below the idle button, status line above it
+	tr := p.win.timerRect()
+	timer, _, _ := pCreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(utf16("BUTTON"))),
+		uintptr(unsafe.Pointer(utf16(""))),
+		uintptr(WS_CHILD|WS_VISIBLE|WS_TABSTOP|BS_OWNERDRAW),
+		uintptr(tr[0]), uintptr(tr[1]),
+		uintptr(tr[2]), uintptr(tr[3]),
+		uintptr(hWndMain),
+		TIMER_ID,
+		hInst, 0,
+	)
+	hWndTimer = syscall.Handle(timer)
+	sr := p.win.statusRect()
+	statusRC = RECT{int32(sr[0]), int32(sr[1]), int32(sr[0] + sr[2]), int32(sr[1] + sr[3])}
+
 	// Register Ctrl+Q global hotkey
 	pRegisterHotKey.Call(uintptr(hWndMain), HK_QUIT, MOD_CONTROL, VK_Q)
 
 	pShowWindow.Call(uintptr(hWndMain), SW_SHOW)
 	pUpdateWindow.Call(uintptr(hWndMain))
 
+	if onWindowReady != nil {
+		onWindowReady()
+	}
+
 	// Message loop
 	var m MSG
 	for {
@@ -483,47 +643,131 @@ func platformRun() {
 	}
 }
 
-func platformSetCursorPos(x, y int) {
+func (winPlatform) SetCursorPos(x, y int) {
 	pSetCursorPos.Call(uintptr(x), uintptr(y))
 }
 
-func platformGetCursorPos() (int, int) {
+func (winPlatform) GetCursorPos() (int, int) {
 	var pt POINT
 	pGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
 	return int(pt.X), int(pt.Y)
 }
 
-func platformClick() {
-	pMouseEvent.Call(MOUSEEVENTF_LEFTDOWN, 0, 0, 0, 0)
-	pMouseEvent.Call(MOUSEEVENTF_LEFTUP, 0, 0, 0, 0)
+func (winPlatform) ScreenSize() (int, int) {
+	w, _, _ := pGetSystemMetrics.Call(SM_CXSCREEN)
+	h, _, _ := pGetSystemMetrics.Call(SM_CYSCREEN)
+	return int(w), int(h)
 }
 
-func platformPreventSleep() {
+func (winPlatform) IdleTime() time.Duration {
+	lii := LASTINPUTINFO{CbSize: uint32(unsafe.Sizeof(LASTINPUTINFO{}))}
+	pGetLastInputInfo.Call(uintptr(unsafe.Pointer(&lii)))
+	now, _, _ := pGetTickCount.Call()
+	// uint32 tick arithmetic survives the 49.7-day wrap
+	return time.Duration(uint32(now)-lii.DwTime) * time.Millisecond
+}
+
+// buttonFlags holds the down and up flags for each button.
+var buttonFlags = map[mouseButton][2]uint32{
+	buttonLeft:   {MOUSEEVENTF_LEFTDOWN, MOUSEEVENTF_LEFTUP},
+	buttonRight:  {MOUSEEVENTF_RIGHTDOWN, MOUSEEVENTF_RIGHTUP},
+	buttonMiddle: {MOUSEEVENTF_MIDDLEDOWN, MOUSEEVENTF_MIDDLEUP},
+}
+
+func sendMouse(flags, data uint32) {
+	in := MOUSE_INPUT{Type: INPUT_MOUSE, Mi: MOUSEINPUT{DwFlags: flags, MouseData: data}}
+	pSendInput.Call(1, uintptr(unsafe.Pointer(&in)), unsafe.Sizeof(in))
+}
+
+// MouseDown ignores count: Windows makes a double-click out of two clicks
+// that arrive close enough together.
+func (winPlatform) MouseDown(b mouseButton, count int) {
+	sendMouse(buttonFlags[b][0], 0)
+}
+
+func (winPlatform) MouseUp(b mouseButton, count int) {
+	sendMouse(buttonFlags[b][1], 0)
+}
+
+func (winPlatform) Scroll(ticks int) {
+	// A positive wheel delta scrolls up, away from the user
+	sendMouse(MOUSEEVENTF_WHEEL, uint32(int32(-ticks*WHEEL_DELTA)))
+}
+
+var virtualKeys = map[key]uint16{keyF15: VK_F15, keyShift: VK_SHIFT}
+
+func (winPlatform) TapKey(k key) {
+	vk := virtualKeys[k]
+	in := [2]KEYBD_INPUT{
+		{Type: INPUT_KEYBOARD, Ki: KEYBDINPUT{WVk: vk}},
+		{Type: INPUT_KEYBOARD, Ki: KEYBDINPUT{WVk: vk, DwFlags: KEYEVENTF_KEYUP}},
+	}
+	pSendInput.Call(uintptr(len(in)), uintptr(unsafe.Pointer(&in[0])), unsafe.Sizeof(in[0]))
+}
+
+func (winPlatform) CursorOverButton() bool {
+	var pt POINT
+	pGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	// POINT is passed by value: x in the low 32 bits, y in the high 32 bits
+	h, _, _ := pWindowFromPoint.Call(uintptr(uint32(pt.X)) | uintptr(uint32(pt.Y))<<32)
+	return syscall.Handle(h) == hWndBtn
+}
+
+func (winPlatform) PreventSleep() {
 	pSetThreadExecutionState.Call(ES_CONTINUOUS | ES_DISPLAY_REQUIRED | ES_SYSTEM_REQUIRED)
 }
 
-func platformAllowSleep() {
+func (winPlatform) AllowSleep() {
 	pSetThreadExecutionState.Call(ES_CONTINUOUS)
 }
 
-func platformMoveButton(x, y int) {
-	pMoveWindow.Call(uintptr(hWndBtn), uintptr(x), uintptr(y), btnW, btnH, 1)
+func (p winPlatform) MoveButton(x, y int) {
+	pMoveWindow.Call(uintptr(hWndBtn), uintptr(x), uintptr(y), uintptr(p.win.ButtonWidth), uintptr(p.win.ButtonHeight), 1)
 }
 
-func platformClientToScreen(x, y int) (int, int) {
+func (winPlatform) ClientToScreen(x, y int) (int, int) {
 	pt := POINT{X: int32(x), Y: int32(y)}
 	pClientToScreen.Call(uintptr(hWndMain), uintptr(unsafe.Pointer(&pt)))
 	return int(pt.X), int(pt.Y)
 }
 
-func platformSetButtonActive(isActive bool) {
+func (winPlatform) SetButtonActive(isActive bool) {
 	pInvalidateRect.Call(uintptr(hWndBtn), 0, 1)
 }
 
-func platformReinforceTopmost() {
+func (winPlatform) SetModeLabel(text string) {
+	textMu.Lock()
+	modeText = text
+	textMu.Unlock()
+	if hWndMode != 0 {
+		pInvalidateRect.Call(uintptr(hWndMode), 0, 1)
+	}
+}
+
+func (winPlatform) SetStatus(text string) {
+	textMu.Lock()
+	statusText = text
+	textMu.Unlock()
+	if hWndMain != 0 {
+		pInvalidateRect.Call(uintptr(hWndMain), uintptr(unsafe.Pointer(&statusRC)), 1)
+	}
+}
+
+func (winPlatform) SetTimerLabel(text string) {
+	textMu.Lock()
+	timerText = text
+	textMu.Unlock()
+	if hWndTimer != 0 {
+		pInvalidateRect.Call(uintptr(hWndTimer), 0, 1)
+	}
+}
+
+func (winPlatform) ReinforceTopmost() {
 	pSetWindowPos.Call(uintptr(hWndMain), HWND_TOPMOST, 0, 0, 0, 0, SWP_NOMOVE|SWP_NOSIZE)
 }
 
-func platformQuit() {
-	pDestroyWindow.Call(uintptr(hWndMain))
+// Quit may be called from any goroutine; DestroyWindow only works on the
+// thread that owns the window, so let DefWindowProc do it via WM_CLOSE.
+func (winPlatform) Quit() {
+	pPostMessageW.Call(uintptr(hWndMain), WM_CLOSE, 0, 0)
 }
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Windows has no zoneinfo of its own
+)
+
+// ── Schedule ────────────────────────────────────────────────────────────────
+// An optional weekly schedule starts the engine when a window opens and
+// stops it when it closes, e.g. Mon–Fri 09:00–12:30 and 13:30–18:30. Times
+// are wall-clock times in the configured time zone: on DST days a window
+// edge in the skipped hour takes effect at the change, and one in the
+// repeated hour applies both times round. The user can still start or stop
+// by hand in between; the scheduler only acts on transitions.
+
+// schedulePoll caps how long the scheduler sleeps between checks, so clock
+// changes, DST and system suspend are picked up within a minute.
+const schedulePoll = time.Minute
+
+type scheduleWindow struct {
+	Days  string `json:"days"`  // e.g. "mon-fri" or "mon,wed,fri"
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM", up to "24:00"; must be after start
+}
+
+type scheduleConfig struct {
+	TimeZone string           `json:"time_zone"` // IANA name, e.g. "Europe/Berlin"; empty = local
+	Windows  []scheduleWindow `json:"windows"`   // empty = no schedule
+}
+
+func (c scheduleConfig) enabled() bool { return len(c.Windows) > 0 }
+
+func (c scheduleConfig) validate() error {
+	_, err := c.compile()
+	return err
+}
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// span is [start, end) in minutes since midnight.
+type span struct{ start, end int }
+
+type schedule struct {
+	loc   *time.Location
+	spans [7][]span // by time.Weekday
+}
+
+func (c scheduleConfig) compile() (*schedule, error) {
+	s := &schedule{loc: time.Local}
+	if c.TimeZone != "" {
+		loc, err := time.LoadLocation(c.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: time_zone: %w", err)
+		}
+		s.loc = loc
+	}
+
+	for i, w := range c.Windows {
+		days, err := parseDays(w.Days)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: window %d: %w", i+1, err)
+		}
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: window %d: start: %w", i+1, err)
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: window %d: end: %w", i+1, err)
+		}
+		if end <= start {
+			return nil, fmt.Errorf("schedule: window %d: end %s is not after start %s (split windows at midnight)", i+1, w.End, w.Start)
+		}
+		for d, on := range days {
+			if on {
+				s.spans[d] = append(s.spans[d], span{start, end})
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseDays parses a comma-separated list of days and ranges, e.g.
+// "mon-fri,sun". Ranges may wrap: "fri-mon".
+func parseDays(v string) ([7]bool, error) {
+	var days [7]bool
+	if strings.TrimSpace(v) == "" {
+		return days, fmt.Errorf("days is empty")
+	}
+	for _, part := range strings.Split(v, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		a, err := parseDay(from)
+		if err != nil {
+			return days, err
+		}
+		b := a
+		if isRange {
+			if b, err = parseDay(to); err != nil {
+				return days, err
+			}
+		}
+		for d := a; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == b {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseDay(v string) (int, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	for i, d := range weekdays {
+		if v == d {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q (use %s)", v, strings.Join(weekdays, ", "))
+}
+
+// parseClock parses "HH:MM" into minutes since midnight; "24:00" is allowed.
+func parseClock(v string) (int, error) {
+	hs, mins, ok := strings.Cut(v, ":")
+	h, errH := strconv.Atoi(hs)
+	m, errM := strconv.Atoi(mins)
+	if !ok || len(mins) != 2 || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("want HH:MM between 00:00 and 24:00, got %q", v)
+	}
+	return h*60 + m, nil
+}
+
+// Active reports whether t falls inside a window.
+func (s *schedule) Active(t time.Time) bool {
+	lt := t.In(s.loc)
+	m := lt.Hour()*60 + lt.Minute()
+	for _, sp := range s.spans[lt.Weekday()] {
+		if m >= sp.start && m < sp.end {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first window edge after t where Active changes. It
+// reports false if the schedule never changes (no windows, or always on).
+func (s *schedule) Next(t time.Time) (time.Time, bool) {
+	now := s.Active(t)
+	lt := t.In(s.loc)
+	var next time.Time
+	consider := func(c time.Time) {
+		if c.After(t) && s.Active(c) != now && (next.IsZero() || c.Before(next)) {
+			next = c
+		}
+	}
+
+	// A week and a day covers every edge, whatever weekday we start on
+	for d := 0; d <= 7; d++ {
+		day := time.Date(lt.Year(), lt.Month(), lt.Day()+d, 0, 0, 0, 0, s.loc)
+		end := time.Date(lt.Year(), lt.Month(), lt.Day()+d+1, 0, 0, 0, 0, s.loc)
+
+		// On a DST day an edge in the skipped hour takes effect at the
+		// change itself, and one in the repeated hour happens twice
+		_, change := day.ZoneBounds()
+		if !change.IsZero() && change.Before(end) {
+			consider(change)
+		}
+		for _, z := range [2]time.Time{day, end.Add(-time.Nanosecond)} {
+			_, off := z.Zone()
+			zone := time.FixedZone("", off)
+			for _, sp := range s.spans[day.Weekday()] {
+				for _, edge := range [2]int{sp.start, sp.end} {
+					// time.Date normalises 24:00 to midnight
+					consider(time.Date(day.Year(), day.Month(), day.Day(), edge/60, edge%60, 0, 0, zone))
+				}
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// ── Scheduler ───────────────────────────────────────────────────────────────
+
+// clock is the scheduler's view of time, swappable for tests.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// scheduler starts and stops the engine on schedule edges, through the same
+// Start/Stop the button uses, and shows the next edge in the window.
+type scheduler struct {
+	e     *engine
+	s     *schedule
+	clock clock
+
+	mu     sync.Mutex
+	on     bool // state applied at the last edge
+	status string
+	cancel context.CancelFunc
+}
+
+func newScheduler(e *engine, s *schedule, c clock) *scheduler {
+	return &scheduler{e: e, s: s, clock: c}
+}
+
+// Start applies the current state at once, then follows the schedule until
+// Stop. Call it when the window is ready.
+func (sc *scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	sc.mu.Lock()
+	sc.cancel = cancel
+	now := sc.clock.Now()
+	sc.on = sc.s.Active(now)
+	sc.drive(now)
+	sc.mu.Unlock()
+
+	go sc.run(ctx)
+}
+
+// Stop ends the scheduler; the engine is left as it is.
+func (sc *scheduler) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.cancel != nil {
+		sc.cancel()
+		sc.cancel = nil
+	}
+}
+
+func (sc *scheduler) run(ctx context.Context) {
+	for {
+		wait := schedulePoll
+		now := sc.clock.Now()
+		if next, ok := sc.s.Next(now); ok {
+			wait = min(wait, next.Sub(now))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-sc.clock.After(wait):
+		}
+		sc.tick(ctx)
+	}
+}
+
+// tick starts or stops the engine if the schedule has crossed an edge.
+func (sc *scheduler) tick(ctx context.Context) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	now := sc.clock.Now()
+	if on := sc.s.Active(now); on != sc.on {
+		sc.on = on
+		sc.drive(now)
+		return
+	}
+	sc.showNext(now)
+}
+
+// drive makes the engine match sc.on and updates the status line.
+func (sc *scheduler) drive(now time.Time) {
+	if sc.on {
+		sc.e.Start()
+	} else {
+		sc.e.Stop()
+	}
+	sc.showNext(now)
+}
+
+func (sc *scheduler) showNext(now time.Time) {
+	text := ""
+	if next, ok := sc.s.Next(now); ok {
+		verb := "Starts"
+		if sc.s.Active(now) {
+			verb = "Stops"
+		}
+		text = verb + " at " + sc.formatEdge(now, next)
+	}
+	if text != sc.status {
+		sc.status = text
+		sc.e.p.SetStatus(text)
+	}
+}
+
+// formatEdge renders t as "18:30" today or "Mon 09:00" on another day, in
+// the schedule's zone, naming the zone when it is not the local one.
+func (sc *scheduler) formatEdge(now, t time.Time) string {
+	lt, ln := t.In(sc.s.loc), now.In(sc.s.loc)
+	layout := "Mon 15:04"
+	if lt.YearDay() == ln.YearDay() && lt.Year() == ln.Year() {
+		layout = "15:04"
+	}
+	if sc.s.loc != time.Local {
+		layout += " MST"
+	}
+	return lt.Format(layout)
+}
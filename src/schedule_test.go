@@ -0,0 +1,211 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// workday is Mon–Fri with a lunch gap.
+var workday = []scheduleWindow{
+	{Days: "mon-fri", Start: "09:00", End: "12:30"},
+	{Days: "mon-fri", Start: "13:30", End: "18:30"},
+}
+
+func berlinSchedule(t *testing.T, ws ...scheduleWindow) *schedule {
+	t.Helper()
+	s, err := scheduleConfig{TimeZone: "Europe/Berlin", Windows: ws}.compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// at parses an RFC 3339 time; the offset tells CET from CEST in the hour
+// that autumn repeats.
+func at(t *testing.T, v string) time.Time {
+	t.Helper()
+	tm, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestScheduleActive(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ws   []scheduleWindow
+		t    string
+		want bool
+	}{
+		{"morning", workday, "2024-01-15T09:00:00+01:00", true},
+		{"before work", workday, "2024-01-15T08:59:59+01:00", false},
+		{"last minute before lunch", workday, "2024-01-15T12:29:59+01:00", true},
+		{"lunch", workday, "2024-01-15T12:30:00+01:00", false},
+		{"after lunch", workday, "2024-01-15T13:30:00+01:00", true},
+		{"evening", workday, "2024-01-15T18:30:00+01:00", false},
+		{"saturday", workday, "2024-01-20T10:00:00+01:00", false},
+		{"other zone", workday, "2024-01-15T08:30:00Z", true},
+		{"fri-mon on sunday", []scheduleWindow{{"fri-mon", "22:00", "24:00"}}, "2024-01-21T23:59:00+01:00", true},
+		{"fri-mon on tuesday", []scheduleWindow{{"fri-mon", "22:00", "24:00"}}, "2024-01-16T23:00:00+01:00", false},
+		{"after the spring gap", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-03-31T03:00:00+02:00", true},
+		{"repeated hour, first", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T02:45:00+02:00", true},
+		{"repeated hour, second", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T02:15:00+01:00", false},
+	} {
+		if got := berlinSchedule(t, tc.ws...).Active(at(t, tc.t)); got != tc.want {
+			t.Errorf("%s: Active(%s) = %v, want %v", tc.name, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ws   []scheduleWindow
+		from string
+		want string // "" if the schedule never changes
+	}{
+		{"to lunch", workday, "2024-01-15T10:00:00+01:00", "2024-01-15T12:30:00+01:00"},
+		{"from the lunch edge", workday, "2024-01-15T12:30:00+01:00", "2024-01-15T13:30:00+01:00"},
+		{"over lunch", workday, "2024-01-15T12:45:00+01:00", "2024-01-15T13:30:00+01:00"},
+		{"over the weekend", workday, "2024-01-19T18:30:00+01:00", "2024-01-22T09:00:00+01:00"},
+		{"fri-mon ends at 24:00", []scheduleWindow{{"fri-mon", "22:00", "24:00"}}, "2024-01-15T23:00:00+01:00", "2024-01-16T00:00:00+01:00"},
+		{"fri-mon opens on friday", []scheduleWindow{{"fri-mon", "22:00", "24:00"}}, "2024-01-16T01:00:00+01:00", "2024-01-19T22:00:00+01:00"},
+		{"24:00 runs into the next day", []scheduleWindow{{"mon", "22:00", "24:00"}, {"tue", "00:00", "06:00"}}, "2024-01-15T23:00:00+01:00", "2024-01-16T06:00:00+01:00"},
+		{"always on", []scheduleWindow{{"sun-sat", "00:00", "24:00"}}, "2024-01-15T10:00:00+01:00", ""},
+		{"no windows", nil, "2024-01-15T10:00:00+01:00", ""},
+
+		// 2024-03-31: 02:00 CET jumps to 03:00 CEST
+		{"start in the spring gap", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-03-31T00:00:00+01:00", "2024-03-31T03:00:00+02:00"},
+		{"end after the spring gap", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-03-31T03:00:00+02:00", "2024-03-31T04:00:00+02:00"},
+		{"end in the spring gap", []scheduleWindow{{"sun", "01:00", "02:30"}}, "2024-03-31T01:30:00+01:00", "2024-03-31T03:00:00+02:00"},
+
+		// 2024-10-27: 03:00 CEST falls back to 02:00 CET
+		{"start in the repeated hour", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T00:30:00+02:00", "2024-10-27T02:30:00+02:00"},
+		{"fall back out of the window", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T02:45:00+02:00", "2024-10-27T02:00:00+01:00"},
+		{"start again in the repeated hour", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T02:10:00+01:00", "2024-10-27T02:30:00+01:00"},
+		{"end after the repeated hour", []scheduleWindow{{"sun", "02:30", "04:00"}}, "2024-10-27T02:30:00+01:00", "2024-10-27T04:00:00+01:00"},
+		{"fall back into the window", []scheduleWindow{{"sun", "01:00", "02:30"}}, "2024-10-27T02:45:00+02:00", "2024-10-27T02:00:00+01:00"},
+	} {
+		got, ok := berlinSchedule(t, tc.ws...).Next(at(t, tc.from))
+		switch {
+		case tc.want == "" && ok:
+			t.Errorf("%s: Next(%s) = %s, want none", tc.name, tc.from, got.Format(time.RFC3339))
+		case tc.want != "" && (!ok || !got.Equal(at(t, tc.want))):
+			t.Errorf("%s: Next(%s) = %s, %v; want %s", tc.name, tc.from, got.Format(time.RFC3339), ok, tc.want)
+		}
+	}
+}
+
+// fakeClock only moves when the test sets it.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []fakeWait
+}
+
+type fakeWait struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+	} else {
+		c.waits = append(c.waits, fakeWait{c.now.Add(d), ch})
+	}
+	return ch
+}
+
+func (c *fakeClock) waiting() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waits) > 0
+}
+
+// set moves the clock to tm, wakes the waiters that are due and returns once
+// something waits on the clock again, i.e. the woken code has run.
+func (c *fakeClock) set(t *testing.T, tm time.Time) {
+	t.Helper()
+	waitUntil(t, "a clock waiter", c.waiting)
+	c.mu.Lock()
+	c.now = tm
+	var rest []fakeWait
+	for _, w := range c.waits {
+		if w.at.After(tm) {
+			rest = append(rest, w)
+		} else {
+			w.ch <- tm
+		}
+	}
+	c.waits = rest
+	c.mu.Unlock()
+	waitUntil(t, "a clock waiter", c.waiting)
+}
+
+func TestSchedulerStartsAndStops(t *testing.T) {
+	cfg := fastConfig()
+	cfg.Mode = modeSleep // running, but leaves the cursor alone
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+	defer e.Wait()
+	defer e.Stop()
+
+	clk := &fakeClock{now: at(t, "2024-01-15T08:00:00+01:00")}
+	sc := newScheduler(e, berlinSchedule(t, workday...), clk)
+	sc.Start()
+	defer sc.Stop()
+
+	expect := func(when string, running bool, status string) {
+		t.Helper()
+		if e.Running() != running {
+			t.Errorf("%s: running = %v, want %v", when, e.Running(), running)
+		}
+		if evs := r.EventsOf(evStatus); len(evs) == 0 || evs[len(evs)-1].Text != status {
+			t.Errorf("%s: status %v, want %q", when, evs, status)
+		}
+	}
+	expect("before work", false, "Starts at 09:00 CET")
+
+	clk.set(t, at(t, "2024-01-15T09:00:00+01:00"))
+	expect("at 09:00", true, "Stops at 12:30 CET")
+	clk.set(t, at(t, "2024-01-15T12:30:00+01:00"))
+	expect("at lunch", false, "Starts at 13:30 CET")
+	clk.set(t, at(t, "2024-01-15T13:30:00+01:00"))
+	expect("after lunch", true, "Stops at 18:30 CET")
+
+	// Stopped by hand: the scheduler leaves it until the next edge
+	e.Stop()
+	clk.set(t, at(t, "2024-01-15T15:00:00+01:00"))
+	expect("stopped by hand", false, "Stops at 18:30 CET")
+	clk.set(t, at(t, "2024-01-15T18:30:00+01:00"))
+	expect("evening", false, "Starts at Tue 09:00 CET")
+	clk.set(t, at(t, "2024-01-16T09:00:00+01:00"))
+	expect("next morning", true, "Stops at 12:30 CET")
+
+	var starts []bool
+	for _, ev := range r.EventsOf(evButtonActive) {
+		starts = append(starts, ev.On)
+	}
+	if want := []bool{true, false, true, false, true}; len(starts) != len(want) {
+		t.Errorf("button active %v, want %v", starts, want)
+	} else {
+		for i := range want {
+			if starts[i] != want[i] {
+				t.Errorf("button active %v, want %v", starts, want)
+				break
+			}
+		}
+	}
+}
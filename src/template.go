@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ── Movement templates ──────────────────────────────────────────────────────
+// With --record, Clicky samples the cursor while it is stopped and saves
+// each of the user's own strokes as a template. The "template" path then
+// replays a recorded stroke, rotated and scaled so it runs exactly from the
+// cursor to the button.
+//
+// Template files are JSON Lines, one stroke per line, with points relative
+// to the first one and sampled every interval_ms:
+//
+//   {"interval_ms":8,"points":[[0,0],[3,1],[9,2],...]}
+//
+// A library is one such file or a directory of *.jsonl files.
+
+const (
+	templateIntervalMs = 8   // sampling rate while recording (125 Hz)
+	strokePauseMs      = 150 // stillness that ends a stroke
+	minStrokePx        = 20  // shorter strokes are clicks or jitter, not moves
+	minStrokePoints    = 5
+	maxTemplateScale   = 4 // most a stroke's sideways wobble is scaled up
+)
+
+type movementTemplate struct {
+	IntervalMs int      `json:"interval_ms"`
+	Points     [][2]int `json:"points"` // relative to the first point
+}
+
+// chord is the straight line from the first point to the last.
+func (t movementTemplate) chord() point {
+	last := t.Points[len(t.Points)-1]
+	return pointOf(last[0], last[1])
+}
+
+func (t movementTemplate) validate() error {
+	if len(t.Points) < 2 {
+		return errors.New("needs at least two points")
+	}
+	if t.Points[0] != [2]int{0, 0} {
+		return errors.New("points must start at [0,0]")
+	}
+	if c := t.chord(); c.dist(point{}) < 1 {
+		return errors.New("ends where it starts")
+	}
+	return nil
+}
+
+// fit returns the template as a path from → to: rotated and scaled about
+// its first point so its chord lands on from → to. Sideways the stroke is
+// scaled up by at most maxTemplateScale, so a short stroke stretched
+// across the screen keeps a hand-sized wobble, and every point stays in
+// onScreen's box. t runs over the samples evenly, so the recorded speed
+// profile survives when easing is none.
+func (t movementTemplate) fit(from, to, screen point) path {
+	// Each point as u along the chord and v across it, in chord lengths;
+	// u·(to-from) + v·perp(to-from) is then the similarity transform
+	c, want := t.chord(), to.sub(from)
+	n := c.X*c.X + c.Y*c.Y
+	perp := point{-want.Y, want.X}
+	if scale := math.Sqrt((want.X*want.X + want.Y*want.Y) / n); scale > maxTemplateScale {
+		perp = perp.scale(maxTemplateScale / scale)
+	}
+	lo, hi := onScreen(from, to, screen)
+
+	pts := make([]point, len(t.Points))
+	for i, q := range t.Points {
+		x, y := float64(q[0]), float64(q[1])
+		u, v := (x*c.X+y*c.Y)/n, (y*c.X-x*c.Y)/n
+		pts[i] = from.add(want.scale(u)).add(perp.scale(v)).clamp(lo, hi)
+	}
+	pts[len(pts)-1] = to
+	return polyline(pts)
+}
+
+// ── Library ─────────────────────────────────────────────────────────────────
+
+// templatePath replays a recorded stroke of about the right length.
+type templatePath struct{ lib []movementTemplate }
+
+func (g templatePath) Path(from, to, screen point, r randSource) path {
+	return g.pick(from.dist(to), r).fit(from, to, screen)
+}
+
+// pick chooses a template whose chord is within a factor of two of dist, or
+// the one closest in length if none is.
+func (g templatePath) pick(dist float64, r randSource) movementTemplate {
+	var near []movementTemplate
+	closest, best := g.lib[0], math.Inf(1)
+	for _, t := range g.lib {
+		l := t.chord().dist(point{})
+		if l >= dist/2 && l <= dist*2 {
+			near = append(near, t)
+		}
+		if off := math.Abs(math.Log(l / math.Max(dist, 1))); off < best {
+			closest, best = t, off
+		}
+	}
+	if len(near) == 0 {
+		return closest
+	}
+	return near[r.Intn(len(near))]
+}
+
+// templatesPath is the library to replay and record into: the configured
+// one, or templates.jsonl next to the default config file.
+func (cc curveConfig) templatesPath() string {
+	if cc.Templates != "" {
+		return cc.Templates
+	}
+	if p := defaultConfigPath(); p != "" {
+		return filepath.Join(filepath.Dir(p), "templates.jsonl")
+	}
+	return "templates.jsonl"
+}
+
+// loadTemplates reads a template file, or every *.jsonl file in a directory.
+func loadTemplates(path string) ([]movementTemplate, error) {
+	files := []string{path}
+	if fi, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if fi.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.jsonl")); err != nil {
+			return nil, err
+		}
+	}
+
+	var lib []movementTemplate
+	for _, f := range files {
+		ts, err := readTemplateFile(f)
+		if err != nil {
+			return nil, err
+		}
+		lib = append(lib, ts...)
+	}
+	if len(lib) == 0 {
+		return nil, fmt.Errorf("%s: no movement templates (record some with --record)", path)
+	}
+	return lib, nil
+}
+
+func readTemplateFile(path string) ([]movementTemplate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lib []movementTemplate
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var t movementTemplate
+		if err := json.Unmarshal(sc.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		lib = append(lib, t)
+	}
+	return lib, sc.Err()
+}
+
+// ── Recorder ────────────────────────────────────────────────────────────────
+
+// strokeSplitter cuts a stream of cursor samples into strokes: a stroke
+// starts when the cursor moves and ends once it has been still for pause
+// samples.
+type strokeSplitter struct {
+	pause int
+	cur   [][2]int // absolute positions of the stroke so far
+	still int
+}
+
+// add takes the next sample. It returns a finished stroke, relative to its
+// first point, or nil. Strokes that are too short or too slight to be a
+// deliberate move are dropped.
+func (s *strokeSplitter) add(x, y int) [][2]int {
+	p := [2]int{x, y}
+	if n := len(s.cur); n == 0 || s.cur[n-1] != p {
+		s.cur = append(s.cur, p)
+		s.still = 0
+		return nil
+	}
+	if s.still++; s.still < s.pause {
+		return nil
+	}
+
+	stroke := s.cur
+	s.cur, s.still = [][2]int{p}, 0 // the next stroke starts from here
+	if len(stroke) < minStrokePoints {
+		return nil
+	}
+	rel := make([][2]int, len(stroke))
+	for i, q := range stroke {
+		rel[i] = [2]int{q[0] - stroke[0][0], q[1] - stroke[0][1]}
+	}
+	if end := rel[len(rel)-1]; pointOf(end[0], end[1]).dist(point{}) < minStrokePx {
+		return nil
+	}
+	return rel
+}
+
+// reset forgets the stroke in progress.
+func (s *strokeSplitter) reset() {
+	s.cur, s.still = nil, 0
+}
+
+// recorder samples the cursor from Start to Stop and appends each stroke
+// to a template file. Samples taken while the engine runs are ours, not the
+// user's, and are thrown away.
+type recorder struct {
+	e    *engine
+	path string
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	n      int   // strokes saved
+	err    error // first write error; recording stops there
+}
+
+func newRecorder(e *engine, path string) *recorder {
+	return &recorder{e: e, path: path}
+}
+
+// Start opens the file and begins sampling. An error ends recording before
+// it starts and is reported by Result.
+func (rc *recorder) Start() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(rc.path), 0755); err != nil {
+		rc.err = err
+		return
+	}
+	f, err := os.OpenFile(rc.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		rc.err = err
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	rc.cancel, rc.done = cancel, make(chan struct{})
+	go rc.run(ctx, f)
+}
+
+// Stop ends recording and waits for the file to be closed.
+func (rc *recorder) Stop() {
+	rc.mu.Lock()
+	cancel, done := rc.cancel, rc.done
+	rc.cancel = nil
+	rc.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Result returns the number of strokes saved and the error that ended
+// recording early, if any.
+func (rc *recorder) Result() (int, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.n, rc.err
+}
+
+func (rc *recorder) run(ctx context.Context, f *os.File) {
+	defer close(rc.done)
+	defer f.Close()
+
+	sp := strokeSplitter{pause: strokePauseMs / templateIntervalMs}
+	t := time.NewTicker(ms(templateIntervalMs))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if rc.e.Running() {
+			sp.reset()
+			continue
+		}
+		stroke := sp.add(rc.e.p.GetCursorPos())
+		if stroke == nil {
+			continue
+		}
+		line, _ := json.Marshal(movementTemplate{IntervalMs: templateIntervalMs, Points: stroke})
+		_, err := f.Write(append(line, '\n'))
+		rc.mu.Lock()
+		if err != nil {
+			rc.err = err
+		} else {
+			rc.n++
+		}
+		rc.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFit(t *testing.T) {
+	tpl := movementTemplate{IntervalMs: 8, Points: [][2]int{{0, 0}, {30, -20}, {70, 15}, {100, 0}}}
+	for _, tc := range []struct {
+		name     string
+		from, to point
+	}{
+		{"same size", point{20, 50}, point{120, 50}},
+		{"shifted", point{500, 300}, point{600, 300}},
+		{"quarter turn", point{500, 300}, point{500, 400}},
+		{"reversed", point{500, 300}, point{400, 300}},
+		{"odd angle, longer", point{17, 905}, point{233, 641}},
+		{"much shorter", point{800, 800}, point{803, 796}},
+	} {
+		p := tpl.fit(tc.from, tc.to, point{1920, 1080})
+		if p(0) != tc.from || p(1) != tc.to {
+			t.Errorf("%s: runs %v → %v, want %v → %v", tc.name, p(0), p(1), tc.from, tc.to)
+		}
+
+		// A similarity transform keeps the shape: every point lands as far
+		// from the start, relative to the chord, as in the template
+		scale := tc.from.dist(tc.to) / tpl.chord().dist(point{})
+		for i, q := range tpl.Points[1 : len(tpl.Points)-1] {
+			got := p(float64(i+1) / float64(len(tpl.Points)-1)).dist(tc.from)
+			want := pointOf(q[0], q[1]).dist(point{}) * scale
+			if math.Abs(got-want) > 1e-9*math.Max(1, want) {
+				t.Errorf("%s: point %d is %.4f px from the start, want %.4f", tc.name, i+1, got, want)
+			}
+		}
+	}
+}
+
+// TestTemplateFitLimits stretches a short stroke across the screen and
+// runs one along its edge: the wobble grows no more than maxTemplateScale
+// and the path stays on screen.
+func TestTemplateFitLimits(t *testing.T) {
+	screen := point{1920, 1080}
+	tpl := movementTemplate{IntervalMs: 8, Points: [][2]int{{0, 0}, {5, -8}, {15, 8}, {20, 0}}}
+
+	from, to := point{100, 540}, point{1800, 540}
+	p := tpl.fit(from, to, screen)
+	wobble := 0.0
+	for i := 0; i <= 3000; i++ {
+		wobble = math.Max(wobble, math.Abs(p(float64(i)/3000).Y-540))
+	}
+	if want := 8.0 * maxTemplateScale; math.Abs(wobble-want) > 1e-6 {
+		t.Errorf("stretched %.0fx: wobbles %.2f px, want %.2f", from.dist(to)/20, wobble, want)
+	}
+
+	// Along the top edge the stroke's upward swing would leave the screen
+	for _, pair := range [][2]point{{{20, 3}, {120, 3}}, {{1900, 1078}, {1700, 1078}}, {{0, 0}, {1919, 0}}} {
+		from, to := pair[0], pair[1]
+		p := tpl.fit(from, to, screen)
+		lo, hi := onScreen(from, to, screen)
+		for i := 0; i <= 1000; i++ {
+			if q := p(float64(i) / 1000); q != q.clamp(lo, hi) {
+				t.Fatalf("%v → %v: off screen at %.1f", from, to, q)
+			}
+		}
+		if p(0) != from || p(1) != to {
+			t.Errorf("%v → %v: runs %v → %v", from, to, p(0), p(1))
+		}
+	}
+}
+
+func TestTemplatePick(t *testing.T) {
+	stroke := func(l int) movementTemplate {
+		return movementTemplate{IntervalMs: 8, Points: [][2]int{{0, 0}, {l / 2, 1}, {l, 0}}}
+	}
+	g := templatePath{[]movementTemplate{stroke(20), stroke(300), stroke(500)}}
+	r := rand.New(rand.NewSource(8))
+	for _, tc := range []struct {
+		dist float64
+		want []int // chords pick may return
+	}{
+		{30, []int{20}},
+		{400, []int{300, 500}},
+		{1500, []int{500}}, // none within a factor of two: the closest
+		{2, []int{20}},
+	} {
+		for i := 0; i < 20; i++ {
+			got := int(g.pick(tc.dist, r).chord().X)
+			if !slices.Contains(tc.want, got) {
+				t.Fatalf("pick(%v) chose a %d px stroke, want one of %v", tc.dist, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestStrokeSplitter(t *testing.T) {
+	const pause = 3
+	// feed runs a stroke from (100, 100) through the given offsets, then
+	// holds still, and returns what the splitter made of it
+	feed := func(offsets ...[2]int) [][2]int {
+		s := strokeSplitter{pause: pause}
+		s.add(100, 100)
+		for _, o := range offsets {
+			if got := s.add(100+o[0], 100+o[1]); got != nil {
+				t.Fatalf("stroke ended while moving: %v", got)
+			}
+		}
+		var stroke [][2]int
+		for i := 0; i < pause; i++ {
+			if got := s.add(100+offsets[len(offsets)-1][0], 100+offsets[len(offsets)-1][1]); got != nil {
+				if i != pause-1 {
+					t.Fatalf("stroke ended after %d still samples, want %d", i+1, pause)
+				}
+				stroke = got
+			}
+		}
+		return stroke
+	}
+
+	got := feed([2]int{5, 0}, [2]int{12, 3}, [2]int{20, 6}, [2]int{26, 8})
+	want := [][2]int{{0, 0}, {5, 0}, {12, 3}, {20, 6}, {26, 8}}
+	if len(got) != len(want) {
+		t.Fatalf("stroke %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("stroke %v, want %v", got, want)
+		}
+	}
+
+	if got := feed([2]int{4, 0}, [2]int{8, 2}, [2]int{12, 4}, [2]int{15, 5}); got != nil {
+		t.Errorf("kept a stroke shorter than %d px: %v", minStrokePx, got)
+	}
+	if got := feed([2]int{20, 0}, [2]int{40, 0}, [2]int{60, 0}); got != nil {
+		t.Errorf("kept a stroke of fewer than %d points: %v", minStrokePoints, got)
+	}
+
+	// Holding still never makes a stroke, however long
+	s := strokeSplitter{pause: pause}
+	for i := 0; i < 10*pause; i++ {
+		if got := s.add(7, 7); got != nil {
+			t.Fatalf("stroke from a cursor at rest: %v", got)
+		}
+	}
+}
+
+func TestReadTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	good := `{"interval_ms":8,"points":[[0,0],[10,2],[30,4]]}`
+
+	p := write("good.jsonl", good+"\n\n"+good+"\n")
+	if lib, err := readTemplateFile(p); err != nil || len(lib) != 2 {
+		t.Errorf("read %d templates (%v), want 2", len(lib), err)
+	}
+
+	for _, tc := range []struct {
+		content string
+		want    string
+	}{
+		{good + "\n{\"points\":\n", ":2: unexpected end of JSON input"},
+		{good + "\n\n" + `{"interval_ms":8,"points":[[1,1],[10,2]]}`, ":3: points must start at [0,0]"},
+		{`{"interval_ms":8,"points":[[0,0]]}`, ":1: needs at least two points"},
+		{`{"interval_ms":8,"points":[[0,0],[5,5],[0,0]]}`, ":1: ends where it starts"},
+	} {
+		p := write("bad.jsonl", tc.content)
+		_, err := readTemplateFile(p)
+		if err == nil || !strings.HasPrefix(err.Error(), p+tc.want) {
+			t.Errorf("error %v, want %s%s", err, p, tc.want)
+		}
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"interval_ms":8,"points":[[0,0],[10,2],[30,4]]}` + "\n"
+	for name, content := range map[string]string{"a.jsonl": line, "b.jsonl": line + line, "notes.txt": "not a template"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lib, err := loadTemplates(dir); err != nil || len(lib) != 3 {
+		t.Errorf("directory: %d templates (%v), want 3", len(lib), err)
+	}
+	if lib, err := loadTemplates(filepath.Join(dir, "b.jsonl")); err != nil || len(lib) != 2 {
+		t.Errorf("file: %d templates (%v), want 2", len(lib), err)
+	}
+	if _, err := loadTemplates(t.TempDir()); err == nil {
+		t.Error("empty directory accepted")
+	}
+}
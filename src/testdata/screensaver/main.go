@@ -0,0 +1,70 @@
+//go:build linux
+
+// Command screensaver is a stand-in org.freedesktop.ScreenSaver service for
+// inhibit_linux_test.go. It owns the name on the session bus, hands out a
+// new cookie for every Inhibit and prints each call on stdout:
+//
+//	ready
+//	inhibit 1
+//	uninhibit 1
+package main
+
+/*
+#cgo pkg-config: dbus-1
+#include <stdio.h>
+#include <string.h>
+#include <dbus/dbus.h>
+
+static void reply(DBusConnection *conn, DBusMessage *msg, dbus_uint32_t *cookie) {
+    DBusMessage *r = dbus_message_new_method_return(msg);
+    if (cookie) {
+        dbus_message_append_args(r, DBUS_TYPE_UINT32, cookie, DBUS_TYPE_INVALID);
+    }
+    dbus_connection_send(conn, r, NULL);
+    dbus_message_unref(r);
+}
+
+static int serve(void) {
+    DBusError err;
+    dbus_error_init(&err);
+    DBusConnection *conn = dbus_bus_get(DBUS_BUS_SESSION, &err);
+    if (!conn) {
+        fprintf(stderr, "screensaver: %s\n", err.message);
+        return 1;
+    }
+    if (dbus_bus_request_name(conn, "org.freedesktop.ScreenSaver", DBUS_NAME_FLAG_DO_NOT_QUEUE, &err) !=
+        DBUS_REQUEST_NAME_REPLY_PRIMARY_OWNER) {
+        fprintf(stderr, "screensaver: cannot own the name\n");
+        return 1;
+    }
+    printf("ready\n");
+    fflush(stdout);
+
+    dbus_uint32_t next = 1;
+    while (dbus_connection_read_write(conn, -1)) {
+        DBusMessage *msg;
+        while ((msg = dbus_connection_pop_message(conn)) != NULL) {
+            if (dbus_message_is_method_call(msg, "org.freedesktop.ScreenSaver", "Inhibit")) {
+                dbus_uint32_t cookie = next++;
+                printf("inhibit %u\n", cookie);
+                reply(conn, msg, &cookie);
+            } else if (dbus_message_is_method_call(msg, "org.freedesktop.ScreenSaver", "UnInhibit")) {
+                dbus_uint32_t cookie = 0;
+                dbus_message_get_args(msg, NULL, DBUS_TYPE_UINT32, &cookie, DBUS_TYPE_INVALID);
+                printf("uninhibit %u\n", cookie);
+                reply(conn, msg, NULL);
+            }
+            fflush(stdout);
+            dbus_message_unref(msg);
+        }
+    }
+    return 0;
+}
+*/
+import "C"
+
+import "os"
+
+func main() {
+	os.Exit(int(C.serve()))
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ── Countdown timer ─────────────────────────────────────────────────────────
+// An optional limit stops the engine a while after it starts ("for 2h") or
+// at a time of day ("until 17:45"). The remaining time is shown on the timer
+// selector below the idle button; clicking the selector steps through the
+// presets, and does so while running too, restarting the countdown from now.
+
+const countdownPoll = time.Second
+
+type timerConfig struct {
+	ForMin     int    `json:"for_min"`     // stop this many minutes after starting (0 = no limit)
+	Until      string `json:"until"`       // or stop at this time of day, "HH:MM"
+	PresetsMin []int  `json:"presets_min"` // limits the timer selector steps through
+}
+
+func (t timerConfig) validate() error {
+	if t.ForMin < 0 {
+		return errors.New("timer: for_min must not be negative")
+	}
+	if t.Until != "" {
+		if t.ForMin > 0 {
+			return errors.New("timer: set for_min or until, not both")
+		}
+		if _, err := parseClock(t.Until); err != nil {
+			return fmt.Errorf("timer: until: %w", err)
+		}
+	}
+	prev := 0
+	for _, p := range t.PresetsMin {
+		if p <= prev {
+			return fmt.Errorf("timer: presets_min must be positive and ascending, got %v", t.PresetsMin)
+		}
+		prev = p
+	}
+	return nil
+}
+
+// countdown stops the engine when its limit runs out. It polls the engine
+// rather than hooking Start, so it also covers starts by the scheduler.
+type countdown struct {
+	e       *engine
+	clock   clock
+	presets []time.Duration
+
+	mu       sync.Mutex
+	forD     time.Duration // limit after start; 0 = none
+	until    int           // limit as minutes since midnight; -1 = none
+	deadline time.Time     // zero while not counting down
+	label    string
+	cancel   context.CancelFunc
+}
+
+func newCountdown(e *engine, tc timerConfig, c clock) *countdown {
+	cd := &countdown{e: e, clock: c, forD: time.Duration(tc.ForMin) * time.Minute, until: -1}
+	if tc.Until != "" {
+		cd.until, _ = parseClock(tc.Until) // validated with the config
+	}
+	for _, p := range tc.PresetsMin {
+		cd.presets = append(cd.presets, time.Duration(p)*time.Minute)
+	}
+	return cd
+}
+
+// Start shows the limit and begins watching the engine until Stop.
+func (cd *countdown) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cd.mu.Lock()
+	cd.cancel = cancel
+	cd.mu.Unlock()
+
+	cd.tick(ctx)
+	go cd.run(ctx)
+}
+
+// Stop ends the countdown; the engine is left as it is.
+func (cd *countdown) Stop() {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.cancel != nil {
+		cd.cancel()
+		cd.cancel = nil
+	}
+}
+
+func (cd *countdown) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cd.clock.After(countdownPoll):
+		}
+		cd.tick(ctx)
+	}
+}
+
+// tick arms the deadline when the engine has started, stops the engine
+// when the deadline passes and refreshes the label.
+func (cd *countdown) tick(ctx context.Context) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := cd.clock.Now()
+	switch {
+	case !cd.e.Running():
+		cd.deadline = time.Time{}
+	case cd.deadline.IsZero():
+		cd.deadline = cd.arm(now)
+	case !now.Before(cd.deadline):
+		cd.e.Stop()
+		cd.deadline = time.Time{}
+	}
+	cd.show(now)
+}
+
+// arm returns the deadline for a start at now, or zero if there is no limit.
+func (cd *countdown) arm(now time.Time) time.Time {
+	switch {
+	case cd.forD > 0:
+		return now.Add(cd.forD)
+	case cd.until >= 0:
+		t := time.Date(now.Year(), now.Month(), now.Day(), cd.until/60, cd.until%60, 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Cycle steps to the next preset longer than what is left, or to no limit
+// after the last one. A running countdown restarts from now.
+func (cd *countdown) Cycle() {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+
+	now := cd.clock.Now()
+	var next time.Duration
+	if left, ok := cd.left(now); !ok {
+		if len(cd.presets) > 0 {
+			next = cd.presets[0]
+		}
+	} else {
+		for _, p := range cd.presets {
+			if p > left {
+				next = p
+				break
+			}
+		}
+	}
+
+	cd.forD, cd.until = next, -1
+	cd.deadline = time.Time{}
+	if cd.e.Running() {
+		cd.deadline = cd.arm(now)
+	}
+	cd.show(now)
+}
+
+// left returns the limit now in force: what remains of a running countdown,
+// or what a start at now would get. It reports false if there is none.
+func (cd *countdown) left(now time.Time) (time.Duration, bool) {
+	if !cd.deadline.IsZero() {
+		return cd.deadline.Sub(now), true
+	}
+	if d := cd.arm(now); !d.IsZero() {
+		return d.Sub(now), true
+	}
+	return 0, false
+}
+
+func (cd *countdown) show(now time.Time) {
+	var text string
+	switch {
+	case !cd.deadline.IsZero():
+		text = formatLeft(cd.deadline.Sub(now)) + " left"
+	case cd.forD > 0:
+		text = "For " + formatLimit(cd.forD)
+	case cd.until >= 0:
+		text = fmt.Sprintf("Until %02d:%02d", cd.until/60, cd.until%60)
+	default:
+		text = "No time limit"
+	}
+	if text != cd.label {
+		cd.label = text
+		cd.e.p.SetTimerLabel(text)
+	}
+}
+
+// formatLeft renders a countdown as h:mm:ss, rounding up so it never shows
+// 0:00:00 while the engine is still running.
+func formatLeft(d time.Duration) string {
+	s := int((d + time.Second - 1) / time.Second)
+	if s < 0 {
+		s = 0
+	}
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// formatLimit renders a limit as "30m", "2h" or "1h30m".
+func formatLimit(d time.Duration) string {
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// startCountdown runs a countdown on a fake clock set to now, over an
+// engine that leaves the cursor alone.
+func startCountdown(t *testing.T, tc timerConfig, now time.Time) (*engine, *fakeClock, *countdown, func(string)) {
+	t.Helper()
+	cfg := fastConfig()
+	cfg.Mode = modeSleep
+	r := newRecordPlatform(cfg.Window, 0, 0)
+	e := newEngine(r, cfg)
+	t.Cleanup(e.Wait)
+	t.Cleanup(e.Stop)
+
+	clk := &fakeClock{now: now}
+	cd := newCountdown(e, tc, clk)
+	cd.Start()
+	t.Cleanup(cd.Stop)
+
+	label := func(want string) {
+		t.Helper()
+		if evs := r.EventsOf(evTimerLabel); len(evs) == 0 || evs[len(evs)-1].Text != want {
+			t.Errorf("timer label %v, want %q", evs, want)
+		}
+	}
+	return e, clk, cd, label
+}
+
+func TestCountdownFor(t *testing.T) {
+	e, clk, _, label := startCountdown(t, timerConfig{ForMin: 90}, at(t, "2024-01-15T09:00:00+01:00"))
+	label("For 1h30m")
+
+	// Armed on the first poll after the engine starts
+	e.Start()
+	clk.set(t, at(t, "2024-01-15T09:00:01+01:00"))
+	label("1:30:00 left")
+	clk.set(t, at(t, "2024-01-15T10:00:01+01:00"))
+	label("0:30:00 left")
+	clk.set(t, at(t, "2024-01-15T10:30:00+01:00"))
+	label("0:00:01 left")
+	if !e.Running() {
+		t.Fatal("stopped before the deadline")
+	}
+
+	clk.set(t, at(t, "2024-01-15T10:30:01+01:00"))
+	if e.Running() {
+		t.Error("still running at the deadline")
+	}
+	label("For 1h30m")
+
+	// A later start gets the full limit again
+	e.Start()
+	clk.set(t, at(t, "2024-01-15T11:00:00+01:00"))
+	label("1:30:00 left")
+}
+
+func TestCountdownUntil(t *testing.T) {
+	for _, tc := range []struct {
+		name, until, start, left, deadline string
+	}{
+		{"same day", "17:45", "2024-01-15T09:00:00+01:00", "8:45:00 left", "2024-01-15T17:45:00+01:00"},
+		{"past midnight", "01:30", "2024-01-15T23:00:00+01:00", "2:30:00 left", "2024-01-16T01:30:00+01:00"},
+		{"already passed", "08:00", "2024-01-15T09:00:00+01:00", "23:00:00 left", "2024-01-16T08:00:00+01:00"},
+		{"right now", "09:00", "2024-01-15T09:00:00+01:00", "24:00:00 left", "2024-01-16T09:00:00+01:00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Armed on the poll a second later, at start
+			start := at(t, tc.start)
+			e, clk, _, label := startCountdown(t, timerConfig{Until: tc.until}, start.Add(-time.Second))
+			label("Until " + tc.until)
+
+			e.Start()
+			clk.set(t, start)
+			label(tc.left)
+
+			deadline := at(t, tc.deadline)
+			clk.set(t, deadline.Add(-time.Second))
+			if !e.Running() {
+				t.Fatal("stopped before the deadline")
+			}
+			clk.set(t, deadline)
+			if e.Running() {
+				t.Error("still running at the deadline")
+			}
+			label("Until " + tc.until)
+		})
+	}
+}
+
+func TestCountdownCycle(t *testing.T) {
+	e, clk, cd, label := startCountdown(t, timerConfig{PresetsMin: []int{30, 60, 120}}, at(t, "2024-01-15T09:00:00+01:00"))
+	label("No time limit")
+
+	// Stopped: the presets in turn, then back to no limit
+	for _, want := range []string{"For 30m", "For 1h", "For 2h", "No time limit", "For 30m", "For 1h"} {
+		cd.Cycle()
+		label(want)
+	}
+
+	// Running: the next preset longer than what is left, counted from now
+	e.Start()
+	clk.set(t, at(t, "2024-01-15T09:00:01+01:00"))
+	label("1:00:00 left")
+	clk.set(t, at(t, "2024-01-15T09:10:01+01:00"))
+	label("0:50:00 left")
+	cd.Cycle()
+	label("1:00:00 left")
+	cd.Cycle()
+	label("2:00:00 left")
+	cd.Cycle()
+	label("No time limit")
+
+	// No limit: it keeps running however long
+	clk.set(t, at(t, "2024-01-16T09:00:00+01:00"))
+	if !e.Running() {
+		t.Error("stopped without a limit")
+	}
+	label("No time limit")
+}
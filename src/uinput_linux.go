@@ -0,0 +1,315 @@
+//go:build linux
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// ── Wayland backend ─────────────────────────────────────────────────────────
+// Wayland compositors do not let clients warp the cursor, so on a Wayland
+// session the pointer is driven through a /dev/uinput virtual mouse that
+// emits absolute positions, buttons and wheel ticks, plus the keep-alive
+// keys, which XTest would only deliver to XWayland clients. Absolute
+// positions, like a VM's tablet, skip pointer acceleration, so the cursor
+// lands where it was sent. The window itself still goes through X11
+// (XWayland).
+
+// ── uinput constants (linux/input-event-codes.h, linux/uinput.h) ────────────
+
+const (
+	EV_SYN = 0x00
+	EV_KEY = 0x01
+	EV_REL = 0x02
+	EV_ABS = 0x03
+
+	SYN_REPORT = 0x00
+	REL_WHEEL  = 0x08
+	ABS_X      = 0x00
+	ABS_Y      = 0x01
+	BTN_LEFT   = 0x110
+	BTN_RIGHT  = 0x111
+	BTN_MIDDLE = 0x112
+
+	KEY_LEFTSHIFT = 42
+	KEY_F15       = 185
+
+	BUS_USB = 0x03
+
+	UI_DEV_CREATE  = 0x5501
+	UI_DEV_DESTROY = 0x5502
+	UI_DEV_SETUP   = 0x405c5503
+	UI_SET_EVBIT   = 0x40045564
+	UI_SET_KEYBIT  = 0x40045565
+	UI_SET_RELBIT  = 0x40045566
+	UI_SET_ABSBIT  = 0x40045567
+	UI_ABS_SETUP   = 0x401c5504
+
+	uinputPath = "/dev/uinput"
+)
+
+// ── uinput types ────────────────────────────────────────────────────────────
+
+type inputID struct {
+	Bustype uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+type uinputSetup struct {
+	ID           inputID
+	Name         [80]byte
+	FfEffectsMax uint32
+}
+
+type inputAbsinfo struct {
+	Value, Minimum, Maximum, Fuzz, Flat, Resolution int32
+}
+
+type uinputAbsSetup struct {
+	Code uint16
+	_    uint16
+	Abs  inputAbsinfo
+}
+
+type inputEvent struct {
+	Sec   int64
+	Usec  int64
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// ── Virtual pointer device ──────────────────────────────────────────────────
+
+type uinputPointer struct {
+	mu  sync.Mutex
+	f   *os.File  // nil in tests
+	out io.Writer // where events go: f, or a buffer in tests
+}
+
+func ioctl(fd uintptr, req, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// newUinputPointer creates the device, with absolute axes spanning a
+// w x h screen.
+func newUinputPointer(w, h int) (*uinputPointer, error) {
+	f, err := os.OpenFile(uinputPath, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return nil, fmt.Errorf("%s is not writable: add a udev rule granting access or join the input group (%w)", uinputPath, err)
+		}
+		return nil, fmt.Errorf("cannot open %s (is the uinput module loaded?): %w", uinputPath, err)
+	}
+
+	fd := f.Fd()
+	setup := uinputSetup{ID: inputID{Bustype: BUS_USB, Vendor: 0x1209, Product: 0xC11C, Version: 1}}
+	copy(setup.Name[:], "Clicky virtual pointer")
+
+	fail := func(err error) (*uinputPointer, error) {
+		f.Close()
+		return nil, fmt.Errorf("uinput setup: %w", err)
+	}
+	for _, bit := range [][2]uintptr{
+		{UI_SET_EVBIT, EV_KEY},
+		{UI_SET_KEYBIT, BTN_LEFT},
+		{UI_SET_KEYBIT, BTN_RIGHT},
+		{UI_SET_KEYBIT, BTN_MIDDLE},
+		{UI_SET_KEYBIT, KEY_LEFTSHIFT},
+		{UI_SET_KEYBIT, KEY_F15},
+		{UI_SET_EVBIT, EV_REL},
+		{UI_SET_RELBIT, REL_WHEEL},
+		{UI_SET_EVBIT, EV_ABS},
+		{UI_SET_ABSBIT, ABS_X},
+		{UI_SET_ABSBIT, ABS_Y},
+	} {
+		if err := ioctl(fd, bit[0], bit[1]); err != nil {
+			return fail(err)
+		}
+	}
+	// One unit per pixel, so compositors map positions 1:1 onto the screen
+	for _, axis := range []uinputAbsSetup{
+		{Code: ABS_X, Abs: inputAbsinfo{Maximum: int32(w - 1)}},
+		{Code: ABS_Y, Abs: inputAbsinfo{Maximum: int32(h - 1)}},
+	} {
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, UI_ABS_SETUP, uintptr(unsafe.Pointer(&axis))); errno != 0 {
+			return fail(errno)
+		}
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, UI_DEV_SETUP, uintptr(unsafe.Pointer(&setup))); errno != 0 {
+		return fail(errno)
+	}
+	if err := ioctl(fd, UI_DEV_CREATE, 0); err != nil {
+		return fail(err)
+	}
+	return &uinputPointer{f: f, out: f}, nil
+}
+
+func (u *uinputPointer) emit(events ...inputEvent) {
+	buf := make([]byte, 0, len(events)*int(unsafe.Sizeof(inputEvent{})))
+	for _, ev := range events {
+		buf = binary.NativeEndian.AppendUint64(buf, uint64(ev.Sec))
+		buf = binary.NativeEndian.AppendUint64(buf, uint64(ev.Usec))
+		buf = binary.NativeEndian.AppendUint16(buf, ev.Type)
+		buf = binary.NativeEndian.AppendUint16(buf, ev.Code)
+		buf = binary.NativeEndian.AppendUint32(buf, uint32(ev.Value))
+	}
+	u.mu.Lock()
+	u.out.Write(buf)
+	u.mu.Unlock()
+}
+
+var synReport = inputEvent{Type: EV_SYN, Code: SYN_REPORT}
+
+// MoveTo puts the pointer at x, y.
+func (u *uinputPointer) MoveTo(x, y int) {
+	u.emit(
+		inputEvent{Type: EV_ABS, Code: ABS_X, Value: int32(x)},
+		inputEvent{Type: EV_ABS, Code: ABS_Y, Value: int32(y)},
+		synReport,
+	)
+}
+
+var uinputButtons = map[mouseButton]uint16{buttonLeft: BTN_LEFT, buttonRight: BTN_RIGHT, buttonMiddle: BTN_MIDDLE}
+
+// Button presses (down) or releases b.
+func (u *uinputPointer) Button(b mouseButton, down bool) {
+	v := int32(0)
+	if down {
+		v = 1
+	}
+	u.emit(inputEvent{Type: EV_KEY, Code: uinputButtons[b], Value: v}, synReport)
+}
+
+// Wheel sends ticks wheel clicks; REL_WHEEL counts positive upwards.
+func (u *uinputPointer) Wheel(ticks int) {
+	u.emit(inputEvent{Type: EV_REL, Code: REL_WHEEL, Value: int32(-ticks)}, synReport)
+}
+
+var uinputKeys = map[key]uint16{keyF15: KEY_F15, keyShift: KEY_LEFTSHIFT}
+
+func (u *uinputPointer) TapKey(k key) {
+	code := uinputKeys[k]
+	u.emit(inputEvent{Type: EV_KEY, Code: code, Value: 1}, synReport)
+	u.emit(inputEvent{Type: EV_KEY, Code: code, Value: 0}, synReport)
+}
+
+func (u *uinputPointer) Close() {
+	ioctl(u.f.Fd(), UI_DEV_DESTROY, 0)
+	u.f.Close()
+}
+
+// ── Platform interface implementation ───────────────────────────────────────
+
+// waylandPlatform is the X11 backend with the pointer replaced by uinput.
+type waylandPlatform struct {
+	*x11Platform
+	ptr  *uinputPointer
+	w, h int // the device's range: the screen when we started
+
+	mu     sync.Mutex
+	lastX  int // where we believe the cursor is
+	lastY  int
+	xwX    int // XWayland's last report
+	xwY    int
+	haveXW bool
+	sent   [][2]int // our latest moves, newest last
+}
+
+// recentMoves is how many of our own moves may still be on their way to
+// XWayland: the compositor passes them on a little later.
+const recentMoves = 8
+
+func isWaylandSession() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland"
+}
+
+func newWaylandPlatform(x *x11Platform) (*waylandPlatform, error) {
+	// XWayland's root window spans every output
+	w, h := x.ScreenSize()
+	ptr, err := newUinputPointer(w, h)
+	if err != nil {
+		return nil, err
+	}
+	return &waylandPlatform{x11Platform: x, ptr: ptr, w: w, h: h}, nil
+}
+
+func (p *waylandPlatform) Run() {
+	p.x11Platform.Run()
+	p.ptr.Close()
+}
+
+// resync takes the cursor position from XWayland whenever its report
+// changes to somewhere we have not just sent it. XWayland only follows the
+// pointer while it is over one of its windows; elsewhere the report is
+// stale and we keep our own reckoning. Caller holds p.mu.
+func (p *waylandPlatform) resync() {
+	x, y := p.x11Platform.GetCursorPos()
+	if !p.haveXW || x != p.xwX || y != p.xwY {
+		if !p.sentRecently(x, y) {
+			p.lastX, p.lastY = x, y
+		}
+	}
+	p.xwX, p.xwY, p.haveXW = x, y, true
+}
+
+// sentRecently reports whether x, y is one of our latest moves, which
+// XWayland may only now be reporting. Caller holds p.mu.
+func (p *waylandPlatform) sentRecently(x, y int) bool {
+	for _, s := range p.sent {
+		if s == [2]int{x, y} {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCursorPos puts the cursor at x, y in one absolute move; there is no
+// acceleration to throw it off.
+func (p *waylandPlatform) SetCursorPos(x, y int) {
+	x, y = max(0, min(p.w-1, x)), max(0, min(p.h-1, y))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resync()
+	p.ptr.MoveTo(x, y)
+	p.lastX, p.lastY = x, y
+	p.sent = append(p.sent, [2]int{x, y})
+	if len(p.sent) > recentMoves {
+		p.sent = p.sent[1:]
+	}
+}
+
+func (p *waylandPlatform) GetCursorPos() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resync()
+	return p.lastX, p.lastY
+}
+
+func (p *waylandPlatform) MouseDown(b mouseButton, count int) {
+	p.ptr.Button(b, true)
+}
+
+func (p *waylandPlatform) MouseUp(b mouseButton, count int) {
+	p.ptr.Button(b, false)
+}
+
+func (p *waylandPlatform) Scroll(ticks int) {
+	p.ptr.Wheel(ticks)
+}
+
+func (p *waylandPlatform) TapKey(k key) {
+	p.ptr.TapKey(k)
+}
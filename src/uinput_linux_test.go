@@ -0,0 +1,66 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unsafe"
+)
+
+// TestUinputStructSizes checks the sizes encoded in the ioctl numbers
+// against the structs passed with them.
+func TestUinputStructSizes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		req  uintptr
+		size uintptr
+	}{
+		{"UI_DEV_SETUP", UI_DEV_SETUP, unsafe.Sizeof(uinputSetup{})},
+		{"UI_ABS_SETUP", UI_ABS_SETUP, unsafe.Sizeof(uinputAbsSetup{})},
+	} {
+		if got := tc.req >> 16 & 0x3fff; got != tc.size {
+			t.Errorf("%s encodes %d bytes, struct has %d", tc.name, got, tc.size)
+		}
+	}
+	if got := unsafe.Sizeof(inputEvent{}); got != 24 {
+		t.Errorf("input_event is %d bytes, want 24", got)
+	}
+}
+
+func TestUinputEvents(t *testing.T) {
+	var buf bytes.Buffer
+	u := &uinputPointer{out: &buf}
+	u.MoveTo(1919, 0)
+	u.Button(buttonRight, true)
+	u.Button(buttonRight, false)
+	u.Wheel(3)
+	u.TapKey(keyF15)
+
+	syn := [3]int{EV_SYN, SYN_REPORT, 0}
+	want := [][3]int{
+		{EV_ABS, ABS_X, 1919}, {EV_ABS, ABS_Y, 0}, syn,
+		{EV_KEY, BTN_RIGHT, 1}, syn,
+		{EV_KEY, BTN_RIGHT, 0}, syn,
+		{EV_REL, REL_WHEEL, -3}, syn, // REL_WHEEL counts upwards
+		{EV_KEY, KEY_F15, 1}, syn,
+		{EV_KEY, KEY_F15, 0}, syn,
+	}
+	var got [][3]int
+	for b := buf.Bytes(); len(b) >= 24; b = b[24:] {
+		got = append(got, [3]int{
+			int(binary.NativeEndian.Uint16(b[16:])),
+			int(binary.NativeEndian.Uint16(b[18:])),
+			int(int32(binary.NativeEndian.Uint32(b[20:]))),
+		})
+	}
+	if len(got) != len(want) || buf.Len()%24 != 0 {
+		t.Fatalf("events %v (%d bytes), want %v", got, buf.Len(), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
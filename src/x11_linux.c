@@ -0,0 +1,522 @@
+#include <pthread.h>
+#include <stdlib.h>
+#include <string.h>
+#include <X11/Xlib.h>
+#include <X11/XKBlib.h>
+#include <X11/Xutil.h>
+#include <X11/Xatom.h>
+#include <X11/keysym.h>
+#include <X11/extensions/XTest.h>
+#include <X11/extensions/scrnsaver.h>
+
+#include "x11_linux.h"
+
+// ── Colors ──────────────────────────────────────────────────────────────────
+
+// Pixel values assume a 24-bit TrueColor visual (Xorg, Xvfb, XWayland)
+#define COLOR_BG    0x2B2B2B
+#define COLOR_BLUE  0x0078D4
+#define COLOR_GREEN 0x107C10
+#define COLOR_MODE  0x3C3C3C
+#define COLOR_MODE_TEXT 0xC8C8C8
+#define COLOR_HINT  0x707070
+#define COLOR_TEXT  0xFFFFFF
+
+// ── Globals ─────────────────────────────────────────────────────────────────
+
+static Display     *dpy          = NULL;
+static Window       rootWindow   = 0;
+static Window       mainWindow   = 0;
+static Window       aliveButton  = 0;
+static Window       modeButton   = 0;
+static Window       timerButton  = 0;
+static GC           gc           = 0;
+static XFontStruct *btnFont      = NULL;
+static XFontStruct *hintFont     = NULL;
+static KeyCode      quitKey      = 0;
+static volatile int buttonActive = 0;
+static int          sleepSuspended = 0;
+
+// Window geometry (client area + button), set from Go before the GUI starts
+static int layoutWinW = 300, layoutWinH = 300;
+static int layoutBtnW = 80,  layoutBtnH = 30;
+static int layoutModeX = 100, layoutModeY = 10, layoutModeW = 100, layoutModeH = 30;
+static int layoutTimerX = 100, layoutTimerY = 175, layoutTimerW = 100, layoutTimerH = 30;
+static int layoutStatusY = 95;
+
+// Mode selector label, set from Go (any thread)
+static pthread_mutex_t modeMu = PTHREAD_MUTEX_INITIALIZER;
+static char modeText[64] = "";
+
+// Status line and timer selector label, set from Go (any thread)
+static pthread_mutex_t textMu = PTHREAD_MUTEX_INITIALIZER;
+static char statusText[128] = "";
+static char timerText[64] = "";
+
+static Atom atomDeleteWindow;
+static Atom atomWMState;
+static Atom atomWMStateAbove;
+static Atom atomQuit;
+
+// Forward declarations for Go callbacks
+extern void goOnButtonClicked();
+extern void goOnHotkeyQuit();
+extern void goOnModeClicked();
+extern void goOnTimerClicked();
+extern void goOnWindowReady();
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+static XFontStruct *loadFont(const char *name) {
+    XFontStruct *f = XLoadQueryFont(dpy, name);
+    if (!f) {
+        f = XLoadQueryFont(dpy, "fixed");
+    }
+    return f;
+}
+
+static void drawCenteredText(Window w, XFontStruct *f, const char *text, int x, int width, int height) {
+    int len = (int)strlen(text);
+    int tw = XTextWidth(f, text, len);
+    int ty = (height + f->ascent - f->descent) / 2;
+    XSetFont(dpy, gc, f->fid);
+    XDrawString(dpy, w, gc, x + (width - tw) / 2, ty, text, len);
+}
+
+static void drawButton(void) {
+    XSetForeground(dpy, gc, COLOR_TEXT);
+    if (buttonActive) {
+        // Core fonts have no U+25CF, so draw the dot by hand
+        const char *text = "Active";
+        int tw = XTextWidth(btnFont, text, (int)strlen(text));
+        int dot = 8, gap = 5;
+        int x = (layoutBtnW - (dot + gap + tw)) / 2;
+        XFillArc(dpy, aliveButton, gc, x, (layoutBtnH - dot) / 2, dot, dot, 0, 360 * 64);
+        drawCenteredText(aliveButton, btnFont, text, x + dot + gap, tw, layoutBtnH);
+    } else {
+        drawCenteredText(aliveButton, btnFont, "Alive", 0, layoutBtnW, layoutBtnH);
+    }
+}
+
+static void drawMode(void) {
+    char text[sizeof(modeText)];
+    pthread_mutex_lock(&modeMu);
+    memcpy(text, modeText, sizeof(text));
+    pthread_mutex_unlock(&modeMu);
+
+    XSetForeground(dpy, gc, COLOR_MODE_TEXT);
+    drawCenteredText(modeButton, hintFont, text, 0, layoutModeW, layoutModeH);
+}
+
+static void drawHint(void) {
+    const char *text = "To close the App press Ctrl+Q";
+    int len = (int)strlen(text);
+    int pad = 12;
+    XSetForeground(dpy, gc, COLOR_HINT);
+    XSetFont(dpy, gc, hintFont->fid);
+    XDrawString(dpy, mainWindow, gc,
+                layoutWinW - pad - XTextWidth(hintFont, text, len),
+                layoutWinH - pad - hintFont->descent,
+                text, len);
+}
+
+static void drawTimer(void) {
+    char text[sizeof(timerText)];
+    pthread_mutex_lock(&textMu);
+    memcpy(text, timerText, sizeof(text));
+    pthread_mutex_unlock(&textMu);
+
+    XSetForeground(dpy, gc, COLOR_MODE_TEXT);
+    drawCenteredText(timerButton, hintFont, text, 0, layoutTimerW, layoutTimerH);
+}
+
+static void drawStatus(void) {
+    char text[sizeof(statusText)];
+    pthread_mutex_lock(&textMu);
+    memcpy(text, statusText, sizeof(text));
+    pthread_mutex_unlock(&textMu);
+
+    int len = (int)strlen(text);
+    XSetForeground(dpy, gc, COLOR_MODE_TEXT);
+    XSetFont(dpy, gc, hintFont->fid);
+    XDrawString(dpy, mainWindow, gc,
+                (layoutWinW - XTextWidth(hintFont, text, len)) / 2,
+                layoutStatusY + (layoutBtnH + hintFont->ascent - hintFont->descent) / 2,
+                text, len);
+}
+
+// sendWMStateAbove asks the window manager to add _NET_WM_STATE_ABOVE.
+static void sendWMStateAbove(void) {
+    XEvent ev;
+    memset(&ev, 0, sizeof(ev));
+    ev.xclient.type         = ClientMessage;
+    ev.xclient.window       = mainWindow;
+    ev.xclient.message_type = atomWMState;
+    ev.xclient.format       = 32;
+    ev.xclient.data.l[0]    = 1; // _NET_WM_STATE_ADD
+    ev.xclient.data.l[1]    = (long)atomWMStateAbove;
+    ev.xclient.data.l[3]    = 1; // source: application
+    XSendEvent(dpy, rootWindow, False,
+               SubstructureRedirectMask | SubstructureNotifyMask, &ev);
+}
+
+static void grabQuitKey(int grab) {
+    // Grab with and without NumLock / CapsLock so Ctrl+Q always fires
+    unsigned int mods[] = {0, LockMask, Mod2Mask, LockMask | Mod2Mask};
+    for (int i = 0; i < 4; i++) {
+        if (grab) {
+            XGrabKey(dpy, quitKey, ControlMask | mods[i], rootWindow, True, GrabModeAsync, GrabModeAsync);
+        } else {
+            XUngrabKey(dpy, quitKey, ControlMask | mods[i], rootWindow);
+        }
+    }
+}
+
+// ── C functions called from Go ──────────────────────────────────────────────
+
+int x11Open(void) {
+    XInitThreads();
+    dpy = XOpenDisplay(NULL);
+    if (!dpy) {
+        return 0;
+    }
+    rootWindow = DefaultRootWindow(dpy);
+    return 1;
+}
+
+void x11SetLayout(int winW, int winH, int btnW, int btnH, int modeX, int modeY, int modeW, int modeH,
+                  int timerX, int timerY, int timerW, int timerH, int statusY) {
+    layoutWinW = winW;
+    layoutWinH = winH;
+    layoutBtnW = btnW;
+    layoutBtnH = btnH;
+    layoutModeX = modeX;
+    layoutModeY = modeY;
+    layoutModeW = modeW;
+    layoutModeH = modeH;
+    layoutTimerX = timerX;
+    layoutTimerY = timerY;
+    layoutTimerW = timerW;
+    layoutTimerH = timerH;
+    layoutStatusY = statusY;
+}
+
+void x11RunGUI(void) {
+    int screen = DefaultScreen(dpy);
+    int startX = (DisplayWidth(dpy, screen) - layoutWinW) / 2;
+    int startY = (DisplayHeight(dpy, screen) - layoutWinH) / 2;
+
+    atomDeleteWindow = XInternAtom(dpy, "WM_DELETE_WINDOW", False);
+    atomWMState      = XInternAtom(dpy, "_NET_WM_STATE", False);
+    atomWMStateAbove = XInternAtom(dpy, "_NET_WM_STATE_ABOVE", False);
+    atomQuit         = XInternAtom(dpy, "CLICKY_QUIT", False);
+
+    XSetWindowAttributes attrs;
+    memset(&attrs, 0, sizeof(attrs));
+    attrs.background_pixel = COLOR_BG;
+    attrs.event_mask = ExposureMask | StructureNotifyMask;
+    mainWindow = XCreateWindow(dpy, rootWindow, startX, startY, layoutWinW, layoutWinH, 0,
+                               CopyFromParent, InputOutput, CopyFromParent,
+                               CWBackPixel | CWEventMask, &attrs);
+
+    XStoreName(dpy, mainWindow, "Clicky");
+    XSetWMProtocols(dpy, mainWindow, &atomDeleteWindow, 1);
+
+    // Fixed size, like the Win32 and Cocoa windows
+    XSizeHints *hints = XAllocSizeHints();
+    hints->flags = PPosition | PMinSize | PMaxSize;
+    hints->x = startX;
+    hints->y = startY;
+    hints->min_width = hints->max_width = layoutWinW;
+    hints->min_height = hints->max_height = layoutWinH;
+    XSetWMNormalHints(dpy, mainWindow, hints);
+    XFree(hints);
+
+    // Request always-on-top before mapping
+    XChangeProperty(dpy, mainWindow, atomWMState, XA_ATOM, 32, PropModeReplace,
+                    (unsigned char *)&atomWMStateAbove, 1);
+
+    // Alive button — centered
+    aliveButton = XCreateSimpleWindow(dpy, mainWindow,
+                                      (layoutWinW - layoutBtnW) / 2, (layoutWinH - layoutBtnH) / 2,
+                                      layoutBtnW, layoutBtnH, 0, 0, COLOR_BLUE);
+    XSelectInput(dpy, aliveButton, ExposureMask | ButtonPressMask | ButtonReleaseMask);
+
+    // Mode selector — top edge, between the top button corners
+    modeButton = XCreateSimpleWindow(dpy, mainWindow,
+                                     layoutModeX, layoutModeY, layoutModeW, layoutModeH,
+                                     0, 0, COLOR_MODE);
+    XSelectInput(dpy, modeButton, ExposureMask | ButtonPressMask | ButtonReleaseMask);
+
+    // Timer selector — below the idle button, status line above it
+    timerButton = XCreateSimpleWindow(dpy, mainWindow,
+                                      layoutTimerX, layoutTimerY, layoutTimerW, layoutTimerH,
+                                      0, 0, COLOR_MODE);
+    XSelectInput(dpy, timerButton, ExposureMask | ButtonPressMask | ButtonReleaseMask);
+
+    gc = XCreateGC(dpy, mainWindow, 0, NULL);
+    btnFont = loadFont("-*-helvetica-bold-r-normal--14-*-*-*-*-*-*-*");
+    hintFont = loadFont("-*-helvetica-medium-r-normal--10-*-*-*-*-*-*-*");
+
+    // Ctrl+Q global hotkey
+    quitKey = XKeysymToKeycode(dpy, XK_q);
+    grabQuitKey(1);
+
+    XMapWindow(dpy, aliveButton);
+    XMapWindow(dpy, modeButton);
+    XMapWindow(dpy, timerButton);
+    XMapWindow(dpy, mainWindow);
+    XFlush(dpy);
+
+    goOnWindowReady();
+
+    int running = 1;
+    while (running) {
+        XEvent ev;
+        XNextEvent(dpy, &ev);
+        switch (ev.type) {
+        case Expose:
+            if (ev.xexpose.count != 0) {
+                break;
+            }
+            if (ev.xexpose.window == aliveButton) {
+                drawButton();
+            } else if (ev.xexpose.window == modeButton) {
+                drawMode();
+            } else if (ev.xexpose.window == timerButton) {
+                drawTimer();
+            } else if (ev.xexpose.window == mainWindow) {
+                drawHint();
+                drawStatus();
+            }
+            break;
+
+        case ButtonRelease:
+            if (ev.xbutton.window == aliveButton && ev.xbutton.button == Button1 &&
+                ev.xbutton.x >= 0 && ev.xbutton.x < layoutBtnW &&
+                ev.xbutton.y >= 0 && ev.xbutton.y < layoutBtnH) {
+                goOnButtonClicked();
+            } else if (ev.xbutton.window == modeButton && ev.xbutton.button == Button1 &&
+                       ev.xbutton.x >= 0 && ev.xbutton.x < layoutModeW &&
+                       ev.xbutton.y >= 0 && ev.xbutton.y < layoutModeH) {
+                goOnModeClicked();
+            } else if (ev.xbutton.window == timerButton && ev.xbutton.button == Button1 &&
+                       ev.xbutton.x >= 0 && ev.xbutton.x < layoutTimerW &&
+                       ev.xbutton.y >= 0 && ev.xbutton.y < layoutTimerH) {
+                goOnTimerClicked();
+            }
+            break;
+
+        case KeyPress:
+            if (ev.xkey.keycode == quitKey && (ev.xkey.state & ControlMask)) {
+                goOnHotkeyQuit();
+            }
+            break;
+
+        case ClientMessage:
+            if (ev.xclient.message_type == atomQuit) {
+                running = 0;
+            } else if ((Atom)ev.xclient.data.l[0] == atomDeleteWindow) {
+                goOnHotkeyQuit();
+            }
+            break;
+        }
+    }
+
+    grabQuitKey(0);
+    x11AllowSleep();
+    if (btnFont) XFreeFont(dpy, btnFont);
+    if (hintFont) XFreeFont(dpy, hintFont);
+    XFreeGC(dpy, gc);
+    XDestroyWindow(dpy, mainWindow);
+    XCloseDisplay(dpy);
+    dpy = NULL;
+}
+
+void x11SetCursorPos(int x, int y) {
+    XWarpPointer(dpy, None, rootWindow, 0, 0, 0, 0, x, y);
+    XFlush(dpy);
+}
+
+void x11GetCursorPos(int *outX, int *outY) {
+    Window root, child;
+    int rootX = 0, rootY = 0, winX, winY;
+    unsigned int mask;
+    XQueryPointer(dpy, rootWindow, &root, &child, &rootX, &rootY, &winX, &winY, &mask);
+    *outX = rootX;
+    *outY = rootY;
+}
+
+void x11ScreenSize(int *outW, int *outH) {
+    int screen = DefaultScreen(dpy);
+    *outW = DisplayWidth(dpy, screen);
+    *outH = DisplayHeight(dpy, screen);
+}
+
+void x11MouseButton(unsigned int button, int down) {
+    XTestFakeButtonEvent(dpy, button, down ? True : False, CurrentTime);
+    XFlush(dpy);
+}
+
+void x11Scroll(int ticks) {
+    // The wheel is buttons 4 (up) and 5 (down), one click per tick
+    unsigned int button = ticks < 0 ? Button4 : Button5;
+    int n = ticks < 0 ? -ticks : ticks;
+    for (int i = 0; i < n; i++) {
+        XTestFakeButtonEvent(dpy, button, True, CurrentTime);
+        XTestFakeButtonEvent(dpy, button, False, CurrentTime);
+    }
+    XFlush(dpy);
+}
+
+// keycodeByName finds the key the XKB keymap calls name (e.g. "FK15"),
+// or returns 0.
+static KeyCode keycodeByName(const char *name) {
+    KeyCode code = 0;
+    XkbDescPtr xkb = XkbGetMap(dpy, 0, XkbUseCoreKbd);
+    if (!xkb) {
+        return 0;
+    }
+    if (XkbGetNames(dpy, XkbKeyNamesMask, xkb) == Success && xkb->names && xkb->names->keys) {
+        for (int k = xkb->min_key_code; k <= xkb->max_key_code; k++) {
+            if (strncmp(xkb->names->keys[k].name, name, XkbKeyNameLength) == 0) {
+                code = (KeyCode)k;
+                break;
+            }
+        }
+    }
+    XkbFreeKeyboard(xkb, 0, True);
+    return code;
+}
+
+int x11TapKey(unsigned long keysym, const char *keyName) {
+    KeyCode code = XKeysymToKeycode(dpy, (KeySym)keysym);
+    if (code == 0 && keyName) {
+        // Not in the keymap under that keysym: the evdev layouts bind
+        // <FK15> to XF86Launch6, say. Any press still counts as activity.
+        code = keycodeByName(keyName);
+    }
+    if (code == 0) {
+        return 0;
+    }
+    XTestFakeKeyEvent(dpy, code, True, CurrentTime);
+    XTestFakeKeyEvent(dpy, code, False, CurrentTime);
+    XFlush(dpy);
+    return 1;
+}
+
+int x11CursorOverButton(void) {
+    // Descend from the root to the deepest window under the pointer
+    Window w = rootWindow, root, child;
+    int rootX, rootY, winX, winY;
+    unsigned int mask;
+    for (;;) {
+        if (!XQueryPointer(dpy, w, &root, &child, &rootX, &rootY, &winX, &winY, &mask)) {
+            return 0;
+        }
+        if (child == None) {
+            break;
+        }
+        w = child;
+    }
+    return w == aliveButton;
+}
+
+long x11IdleMillis(void) {
+    long idle = 0;
+    XScreenSaverInfo *info = XScreenSaverAllocInfo();
+    if (info) {
+        if (XScreenSaverQueryInfo(dpy, rootWindow, info)) {
+            idle = (long)info->idle;
+        }
+        XFree(info);
+    }
+    return idle;
+}
+
+void x11PreventSleep(void) {
+    if (!sleepSuspended) {
+        XScreenSaverSuspend(dpy, True);
+        sleepSuspended = 1;
+    }
+    XResetScreenSaver(dpy);
+    XFlush(dpy);
+}
+
+void x11AllowSleep(void) {
+    if (sleepSuspended) {
+        XScreenSaverSuspend(dpy, False);
+        sleepSuspended = 0;
+        XFlush(dpy);
+    }
+}
+
+void x11MoveButton(int x, int y) {
+    XMoveWindow(dpy, aliveButton, x, y);
+    XFlush(dpy);
+}
+
+void x11ClientToScreen(int cx, int cy, int *outX, int *outY) {
+    Window child;
+    int x = 0, y = 0;
+    XTranslateCoordinates(dpy, mainWindow, rootWindow, cx, cy, &x, &y, &child);
+    *outX = x;
+    *outY = y;
+}
+
+void x11SetButtonActive(int isActive) {
+    buttonActive = isActive;
+    XSetWindowBackground(dpy, aliveButton, isActive ? COLOR_GREEN : COLOR_BLUE);
+    XClearArea(dpy, aliveButton, 0, 0, 0, 0, True);
+    XFlush(dpy);
+}
+
+void x11SetModeLabel(const char *text) {
+    pthread_mutex_lock(&modeMu);
+    strncpy(modeText, text, sizeof(modeText) - 1);
+    pthread_mutex_unlock(&modeMu);
+
+    if (modeButton) {
+        XClearArea(dpy, modeButton, 0, 0, 0, 0, True);
+        XFlush(dpy);
+    }
+}
+
+void x11SetStatus(const char *text) {
+    pthread_mutex_lock(&textMu);
+    strncpy(statusText, text, sizeof(statusText) - 1);
+    pthread_mutex_unlock(&textMu);
+
+    if (mainWindow) {
+        XClearArea(dpy, mainWindow, 0, layoutStatusY, layoutWinW, layoutBtnH, True);
+        XFlush(dpy);
+    }
+}
+
+void x11SetTimerLabel(const char *text) {
+    pthread_mutex_lock(&textMu);
+    strncpy(timerText, text, sizeof(timerText) - 1);
+    pthread_mutex_unlock(&textMu);
+
+    if (timerButton) {
+        XClearArea(dpy, timerButton, 0, 0, 0, 0, True);
+        XFlush(dpy);
+    }
+}
+
+void x11ReinforceTopmost(void) {
+    sendWMStateAbove();
+    XRaiseWindow(dpy, mainWindow);
+    XFlush(dpy);
+}
+
+void x11Quit(void) {
+    XEvent ev;
+    memset(&ev, 0, sizeof(ev));
+    ev.xclient.type         = ClientMessage;
+    ev.xclient.window       = mainWindow;
+    ev.xclient.message_type = atomQuit;
+    ev.xclient.format       = 32;
+    XSendEvent(dpy, mainWindow, False, NoEventMask, &ev);
+    XFlush(dpy);
+}
@@ -0,0 +1,27 @@
+#ifndef X11_LINUX_H
+#define X11_LINUX_H
+
+int  x11Open(void);
+void x11SetLayout(int winW, int winH, int btnW, int btnH, int modeX, int modeY, int modeW, int modeH,
+                  int timerX, int timerY, int timerW, int timerH, int statusY);
+void x11RunGUI(void);
+void x11SetCursorPos(int x, int y);
+void x11GetCursorPos(int *outX, int *outY);
+void x11ScreenSize(int *outW, int *outH);
+void x11MouseButton(unsigned int button, int down);
+void x11Scroll(int ticks);
+int  x11TapKey(unsigned long keysym, const char *keyName);
+int  x11CursorOverButton(void);
+long x11IdleMillis(void);
+void x11PreventSleep(void);
+void x11AllowSleep(void);
+void x11MoveButton(int x, int y);
+void x11ClientToScreen(int cx, int cy, int *outX, int *outY);
+void x11SetButtonActive(int isActive);
+void x11SetModeLabel(const char *text);
+void x11SetStatus(const char *text);
+void x11SetTimerLabel(const char *text);
+void x11ReinforceTopmost(void);
+void x11Quit(void);
+
+#endif